@@ -0,0 +1,436 @@
+package wolfram
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AnswerChange reports whether an answer differs between two results, for monitoring an answer over time (e.g. a
+//	stock price or the weather).
+type AnswerChange struct {
+	Changed bool
+	Old     string // the answer in the previous result, empty if there was none
+	New     string // the answer in the current result, empty if there is none
+}
+
+// CompareAnswers compares the answer of the pod with the given ID in two results, or the primary answer if podID is
+//	empty.  Answers are compared ignoring case and differences in whitespace, so that reformatting by Wolfram Alpha is
+//	not reported as a change.   A nil result is treated as having no answer.
+func CompareAnswers(previous, current *QueryResult, podID string) AnswerChange {
+	oldAnswer := answerForComparison(previous, podID)
+	newAnswer := answerForComparison(current, podID)
+
+	return AnswerChange{
+		Changed: normaliseAnswer(oldAnswer) != normaliseAnswer(newAnswer),
+		Old:     oldAnswer,
+		New:     newAnswer,
+	}
+}
+
+func answerForComparison(result *QueryResult, podID string) string {
+	if result == nil {
+		return ""
+	}
+	if podID == "" {
+		answer, _ := result.FirstAnswer()
+		return answer
+	}
+	if pod, ok := result.PodByID(podID); ok {
+		return pod.plaintext()
+	}
+	return ""
+}
+
+// normaliseAnswer lower cases an answer and collapses runs of whitespace to a single space.
+func normaliseAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
+
+// PrimaryAnswer returns the plaintext of the pod holding the answer: the pod marked primary, otherwise the pod titled
+//	"Result" or "Solution", otherwise a pod from the "Data" scanner.  ok is false if there is no such pod with plaintext.
+func (result *QueryResult) PrimaryAnswer() (string, bool) {
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if pod.Primary {
+			if text := pod.plaintext(); text != "" {
+				return text, true
+			}
+		}
+	}
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if pod.isResult() {
+			if text := pod.plaintext(); text != "" {
+				return text, true
+			}
+		}
+	}
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if strings.EqualFold(pod.Scanner, "Data") {
+			if text := pod.plaintext(); text != "" {
+				return text, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsSuccessful reports whether Wolfram Alpha understood the query without error and returned pods, rather than
+//	checking Success and Error separately.
+func (result *QueryResult) IsSuccessful() bool {
+	return result.Success && result.Error.Err == nil && len(result.Pods) > 0
+}
+
+// DidTimeout reports whether some scanners timed out (TimedOut lists them), so the result may be missing pods that a
+//	longer scantimeout or Recalculate would give.
+func (result *QueryResult) DidTimeout() bool {
+	return result.TimedOut != ""
+}
+
+// DataTypeList returns the categories of data in the result, e.g. ["Financial" "Quantity"], splitting DataTypes.   It is
+//	nil if there are none.
+func (result *QueryResult) DataTypeList() []string {
+	var dataTypes []string
+	for _, dataType := range strings.Split(result.DataTypes, ",") {
+		if dataType = strings.TrimSpace(dataType); dataType != "" {
+			dataTypes = append(dataTypes, dataType)
+		}
+	}
+	return dataTypes
+}
+
+// HasSubstantiveAnswer reports whether the result holds an answer rather than only echoing the interpretation of the
+//	input.  Wolfram Alpha can report success for a query it did not really understand, returning only an input
+//	interpretation pod, which should not be presented as an answer.
+func (result *QueryResult) HasSubstantiveAnswer() bool {
+	if !result.Success {
+		return false
+	}
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if pod.isInput() || pod.Error {
+			continue
+		}
+		if pod.plaintext() != "" {
+			return true
+		}
+		for _, subPod := range pod.SubPods {
+			if subPod.Image.Src != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// plaintext returns the non-empty plaintext of the subpods, one per line.
+func (pod *Pod) plaintext() string {
+	texts := make([]string, 0, len(pod.SubPods))
+	for _, subPod := range pod.SubPods {
+		if text := strings.TrimSpace(subPod.Plaintext); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// AllPlaintext returns the non-empty plaintext of every subpod in the result, trimmed, with pods in position order.
+func (result *QueryResult) AllPlaintext() []string {
+	var texts []string
+	for _, pod := range result.podsByPosition() {
+		for _, subPod := range pod.SubPods {
+			if text := strings.TrimSpace(subPod.Plaintext); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return texts
+}
+
+// PlaintextByTitle returns the non-empty plaintext of the subpods keyed by the title of their pod.  The texts of pods
+//	with the same title are combined in position order; pods with no plaintext are left out.
+func (result *QueryResult) PlaintextByTitle() map[string][]string {
+	texts := make(map[string][]string)
+	for _, pod := range result.podsByPosition() {
+		for _, subPod := range pod.SubPods {
+			if text := strings.TrimSpace(subPod.Plaintext); text != "" {
+				texts[pod.Title] = append(texts[pod.Title], text)
+			}
+		}
+	}
+	return texts
+}
+
+// podsByPosition returns the pods sorted by position, leaving the result as it is.
+func (result *QueryResult) podsByPosition() []Pod {
+	sorted := QueryResult{Pods: append([]Pod(nil), result.Pods...)}
+	sorted.SortPods()
+	return sorted.Pods
+}
+
+// isResult reports whether the pod is the one titled (or with the ID) "Result" or "Solution".
+func (pod *Pod) isResult() bool {
+	for _, name := range []string{"Result", "Solution"} {
+		if pod.ID == name || strings.EqualFold(strings.TrimSpace(pod.Title), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInput reports whether the pod only echoes the interpretation of the input rather than answering it.
+func (pod *Pod) isInput() bool {
+	return strings.HasPrefix(pod.ID, "Input") || strings.HasPrefix(strings.ToLower(pod.Title), "input")
+}
+
+// FirstAnswer returns the best textual answer in the result.  This is the primary answer (see PrimaryAnswer), otherwise
+//	the plaintext of the first pod that is not an interpretation of the input, otherwise for results holding only plots
+//	with no plaintext, the alt text of the first plot image.  ok is false if the result has no textual answer at all.
+func (result *QueryResult) FirstAnswer() (string, bool) {
+	if answer, ok := result.PrimaryAnswer(); ok {
+		return answer, true
+	}
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if pod.isInput() {
+			continue
+		}
+		if text := pod.plaintext(); text != "" {
+			return text, true
+		}
+	}
+	for _, plot := range result.PlotAnswers() {
+		if plot.Alt != "" {
+			return plot.Alt, true
+		}
+	}
+	return "", false
+}
+
+// PlotAnswer pairs a plot pod with the alt text of its image, which for plots usually holds the only textual form of
+//	the answer.
+type PlotAnswer struct {
+	Pod *Pod
+	Alt string
+}
+
+// PlotAnswers returns each plot pod in the result with the alt text of its images (one per line if the pod has more
+//	than one image).  Plot pods are those with a "Plot" ID or title, or from the plotting scanner.
+func (result *QueryResult) PlotAnswers() []PlotAnswer {
+	var plots []PlotAnswer
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if !pod.isPlot() {
+			continue
+		}
+
+		alts := make([]string, 0, len(pod.SubPods))
+		for _, subPod := range pod.SubPods {
+			if alt := strings.TrimSpace(subPod.Image.Alt); alt != "" {
+				alts = append(alts, alt)
+			}
+		}
+		plots = append(plots, PlotAnswer{Pod: pod, Alt: strings.Join(alts, "\n")})
+	}
+	return plots
+}
+
+// isPlot reports whether the pod holds a plot.
+func (pod *Pod) isPlot() bool {
+	return strings.Contains(pod.ID, "Plot") ||
+		strings.Contains(strings.ToLower(pod.Title), "plot") ||
+		strings.HasPrefix(pod.Scanner, "Plot")
+}
+
+// PrimaryAnswer returns the plaintext of the Result pod of the query, requesting only that pod in plaintext format as the
+//	lowest latency use of the full results API.  Unlike the short answer endpoint the assumptions are available, and
+//	assumed is true if Wolfram Alpha made assumptions that could be changed to give a different answer.  An error
+//	reported by Wolfram Alpha in the result is returned, otherwise ErrNoAnswer if there is no Result pod.
+func (c *Client) PrimaryAnswer(ctx context.Context, query string, params url.Values) (answer string, assumed bool, err error) {
+	primaryParams := cloneParams(params)
+	primaryParams.Set("includepodid", "Result")
+	primaryParams.Set("format", "plaintext")
+
+	result, err := c.getQueryResult(ctx, query, primaryParams)
+	if err != nil {
+		return "", false, err
+	}
+	if result.Error.Err != nil {
+		return "", false, result.Error.Err
+	}
+
+	for _, assumption := range result.Assumptions.Assumption {
+		if len(assumption.Values) > 1 {
+			assumed = true
+			break
+		}
+	}
+
+	if pod, ok := result.PodByID("Result"); ok {
+		if answer = pod.plaintext(); answer != "" {
+			return answer, assumed, nil
+		}
+	}
+	return "", assumed, ErrNoAnswer
+}
+
+// GetAnswer returns only the text of the answer to the query.   The Result pod is requested alone in plaintext (see
+//	PrimaryAnswer), and if there is none the query is repeated for all the pods to find the primary answer among them.
+//	An error reported by Wolfram Alpha in either result is returned, otherwise ErrNoAnswer if neither has an answer.
+func (c *Client) GetAnswer(ctx context.Context, query string, params url.Values) (string, error) {
+	answer, _, err := c.PrimaryAnswer(ctx, query, params)
+	if !errors.Is(err, ErrNoAnswer) {
+		return answer, err
+	}
+
+	plaintextParams := cloneParams(params)
+	plaintextParams.Set("format", "plaintext")
+
+	result, err := c.getQueryResult(ctx, query, plaintextParams)
+	if err != nil {
+		return "", err
+	}
+	if result.Error.Err != nil {
+		return "", result.Error.Err
+	}
+	answer, ok := result.PrimaryAnswer()
+	if !ok {
+		return "", ErrNoAnswer
+	}
+	return answer, nil
+}
+
+// VoiceAnswer returns a concise answer to the query as a single sentence suitable for text to speech, in metric units.
+//	The spoken answer endpoint is preferred, falling back to the primary answer of the full results with units spelled
+//	out if there is no spoken answer.   ErrNoAnswer is returned if neither has an answer.  Other errors of the spoken
+//	endpoint, e.g. an invalid AppID or too many requests, and errors reported in the full results are returned as they
+//	are.
+func (c *Client) VoiceAnswer(ctx context.Context, query string) (string, error) {
+	spoken, err := c.spokenAnswer(ctx, query)
+	if err == nil {
+		return voiceSentence(spoken), nil
+	} else if !errors.Is(err, ErrNoShortAnswer) {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("format", "plaintext")
+	result, err := c.getQueryResult(ctx, query, params)
+	if err != nil {
+		return "", err
+	}
+	if result.Error.Err != nil {
+		return "", result.Error.Err
+	}
+
+	answer, ok := result.FirstAnswer()
+	if !ok {
+		return "", ErrNoAnswer
+	}
+	return voiceSentence(spellOutUnits(annotationPattern.ReplaceAllString(answer, ""))), nil
+}
+
+// spokenAnswer returns the answer of the spoken endpoint.  If there is no spoken answer the error matches
+//	ErrNoShortAnswer.
+func (c *Client) spokenAnswer(ctx context.Context, query string) (string, error) {
+	result, err := c.spokenResult(ctx, query, Metric, 0)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// annotationPattern matches the parenthesised annotations of plaintext answers, e.g. the "(kilometers)" of
+//	"343.9 km  (kilometers)", which are noise when spoken.
+var annotationPattern = regexp.MustCompile(`\s*\([^()]*\)`)
+
+// spokenUnits are the unit abbreviations in plaintext answers with how they are spoken.   Abbreviations are only replaced
+//	when they follow a number, so that words in the answer are not mistaken for units.
+var spokenUnits = []struct {
+	abbreviation string
+	spoken       string
+}{
+	{"°C", "degrees Celsius"},
+	{"°F", "degrees Fahrenheit"},
+	{"km/h", "kilometers per hour"},
+	{"mph", "miles per hour"},
+	{"m/s", "meters per second"},
+	{"km", "kilometers"},
+	{"cm", "centimeters"},
+	{"mm", "millimeters"},
+	{"mi", "miles"},
+	{"ft", "feet"},
+	{"kg", "kilograms"},
+	{"lb", "pounds"},
+	{"oz", "ounces"},
+	{"mL", "milliliters"},
+	{"m", "meters"},
+	{"g", "grams"},
+	{"L", "liters"},
+	{"min", "minutes"},
+	{"%", "percent"},
+}
+
+var spokenUnitPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(spokenUnits))
+	for i, unit := range spokenUnits {
+		patterns[i] = regexp.MustCompile(`(\d)\s*` + regexp.QuoteMeta(unit.abbreviation) + `(\W|$)`)
+	}
+	return patterns
+}()
+
+// spellOutUnits replaces unit abbreviations following numbers with the words for them, e.g. "3.4 km" becomes
+//	"3.4 kilometers", and scientific notation with how it is spoken.
+func spellOutUnits(answer string) string {
+	answer = strings.ReplaceAll(answer, "×10^", " times 10 to the power of ")
+	for i, pattern := range spokenUnitPatterns {
+		answer = pattern.ReplaceAllString(answer, "${1} "+spokenUnits[i].spoken+"${2}")
+	}
+	return answer
+}
+
+// voiceSentence reduces an answer to a single sentence: the first line, with the separators used in plaintext replaced,
+//	whitespace collapsed and ending with a single full stop.
+func voiceSentence(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if i := strings.IndexByte(answer, '\n'); i >= 0 {
+		answer = answer[:i]
+	}
+	answer = strings.ReplaceAll(answer, " | ", ", ")
+	answer = strings.Join(strings.Fields(answer), " ")
+	answer = strings.TrimRight(answer, " .,;:|-")
+	if answer == "" {
+		return ""
+	}
+	if strings.HasSuffix(answer, "!") || strings.HasSuffix(answer, "?") {
+		return answer
+	}
+	return answer + "."
+}
+
+// SourceLinks returns the sources of the result and of each of its pods as a single list for citation, e.g. a "data
+//	provided by" footer.  Sources are deduplicated by URL, keeping the first seen, with those of the result first.
+func (result *QueryResult) SourceLinks() []Source {
+	var links []Source
+	seen := map[string]bool{}
+	add := func(sources Sources) {
+		for _, source := range sources.Source {
+			if source.URL == "" || seen[source.URL] {
+				continue
+			}
+			seen[source.URL] = true
+			links = append(links, source)
+		}
+	}
+
+	add(result.Sources)
+	for _, pod := range result.Pods {
+		add(pod.Sources)
+	}
+	return links
+}
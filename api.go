@@ -0,0 +1,27 @@
+package wolfram
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// API is the interface of the main methods of Client, for code that queries Wolfram Alpha to depend on in place of
+//	*Client so that it can be tested without making requests (see the fakes package).
+type API interface {
+	GetQueryResult(query string, params url.Values) (*QueryResult, error)
+	GetQueryResultWithAssumption(query string, assumptionInput string, params url.Values) (*QueryResult, error)
+	GetQueryResultWithPodStates(query string, podStates []string, params url.Values) (*QueryResult, error)
+	GetFullResult(ctx context.Context, query string, params url.Values) (*QueryResult, error)
+	GetAnswer(ctx context.Context, query string, params url.Values) (string, error)
+	GetShortAnswerQuery(query string, units Unit, timeout int) (string, error)
+	GetSpokenAnswerQuery(query string, units Unit, timeout int) (string, error)
+	GetSimpleQuery(query string, params url.Values) (io.ReadCloser, string, error)
+	GetLLMQuery(query string, params url.Values) (string, error)
+	GetFastQueryRecognizer(query string, mode Mode, params ...url.Values) (*FastQueryResult, error)
+	GetValidateQuery(query string, params url.Values) (*ValidationResult, error)
+	GetConversationalQuery(query string, params url.Values) (*ConversationResult, error)
+	FollowUpConversation(previous *ConversationResult, query string) (*ConversationResult, error)
+}
+
+var _ API = (*Client)(nil)
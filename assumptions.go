@@ -0,0 +1,210 @@
+package wolfram
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Clash is the type of an assumption about the meaning of a word of a query, e.g. "dow chemical" as a company.
+const Clash = "Clash"
+
+// MultiClash is the type of an assumption covering several words of a query at once, each with its own meanings.
+const MultiClash = "MultiClash"
+
+/*
+	example MultiClash assumption for 'log 20 dow':
+
+	{
+		"type":"MultiClash",
+		"word":"",
+		"template":"Assuming ${word1} is ${desc1}. Use \"${word2}\" as ${desc2} instead",
+		"count":3,
+		"values":[
+			{"name":"Math", "word":"log", "desc":"a math function", "input":"*MC.log-_*Math-"},
+			{"name":"Word", "word":"log", "desc":"a word", "input":"*MC.log-_*Word-"},
+			{"name":"Financial", "word":"dow", "desc":"a financial entity", "input":"*MC.~-_*Financial-"}
+		]
+	}
+*/
+
+// ClashWord is one of the words of a MultiClash assumption with the meanings it can take, e.g. to present as a dropdown.
+//	The first option is the meaning assumed.
+type ClashWord struct {
+	Word    string
+	Options []Value
+}
+
+// MultiClashWords returns the words of a MultiClash assumption, in the order they appear, each with its options.  Values
+//	that do not name a word apply to the word of the assumption.
+func (assumption *Assumption) MultiClashWords() ([]ClashWord, error) {
+	if assumption.Type != MultiClash {
+		return nil, errors.Errorf("assumption is %s, not %s", assumption.Type, MultiClash)
+	}
+
+	var words []ClashWord
+	index := map[string]int{}
+	for _, value := range assumption.Values {
+		word := assumption.valueWord(value)
+		i, ok := index[word]
+		if !ok {
+			i = len(words)
+			index[word] = i
+			words = append(words, ClashWord{Word: word})
+		}
+		words[i].Options = append(words[i].Options, value)
+	}
+	return words, nil
+}
+
+// MultiClashParams returns the assumption parameters to request the chosen meanings of a MultiClash assumption, one
+//	parameter per word chosen.   choices maps a word to the name of the option chosen for it; words not in choices keep
+//	the meaning assumed by Wolfram Alpha.  The parameters can be added to those of a query.
+func (assumption *Assumption) MultiClashParams(choices map[string]string) (url.Values, error) {
+	words, err := assumption.MultiClashWords()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(words))
+	for _, word := range words {
+		known[word.Word] = true
+	}
+	for word := range choices {
+		if !known[word] {
+			return nil, errors.Errorf("'%s' is not a word of the assumption", word)
+		}
+	}
+
+	// parameters follow the order of the words so that the request is deterministic
+	params := url.Values{}
+	for _, word := range words {
+		name, ok := choices[word.Word]
+		if !ok {
+			continue
+		}
+
+		input := ""
+		for _, option := range word.Options {
+			if option.Name == name {
+				input = option.Input
+				break
+			}
+		}
+		if input == "" {
+			return nil, errors.Errorf("'%s' is not an option for '%s'", name, word.Word)
+		}
+		params.Add("assumption", input)
+	}
+	return params, nil
+}
+
+// InputForValue returns the Input of the value of the assumption with the given Name, e.g. "Company" gives
+//	"*C.dow+chemical-_*Company-", for use with GetQueryResultWithAssumption.  The first value is the meaning assumed by
+//	Wolfram Alpha, so its name forces that meaning.   ok is false if no value has the name.
+func (assumption *Assumption) InputForValue(name string) (input string, ok bool) {
+	for _, value := range assumption.Values {
+		if value.Name == name {
+			return value.Input, true
+		}
+	}
+	return "", false
+}
+
+// GetQueryResultWithAssumption gets the query result with the given assumption applied, e.g. to choose the meaning of an
+//	ambiguous query offered by ForActionDisplay.  assumptionInput is the Input of a Value (the Action of an
+//	ActionAssumption) as it was given in the response, e.g. "*C.dow+chemical-_*Company-", and must not be encoded as
+//	it is encoded by this method (see EncodeAssumption).
+func (c *Client) GetQueryResultWithAssumption(query string, assumptionInput string, params url.Values) (*QueryResult, error) {
+	return c.GetQueryResultWithAssumptions(query, []string{assumptionInput}, params)
+}
+
+// GetQueryResultWithAssumptions gets the query result with several assumptions applied together, e.g. to resolve the
+//	clashes of two words of a query at once.   Each input is sent as its own assumption parameter, in order, and is
+//	encoded as by GetQueryResultWithAssumption.
+func (c *Client) GetQueryResultWithAssumptions(query string, assumptionInputs []string, params url.Values) (*QueryResult, error) {
+	if len(assumptionInputs) == 0 {
+		return nil, errors.New("no assumption inputs given")
+	}
+	encoded := make([]string, 0, len(assumptionInputs))
+	for _, assumptionInput := range assumptionInputs {
+		if assumptionInput == "" {
+			return nil, errors.New("no assumption input given")
+		}
+		encoded = append(encoded, "assumption="+EncodeAssumption(assumptionInput))
+	}
+	return c.getQueryResult(context.Background(), query, params, encoded...)
+}
+
+// QueryAllAssumptions gets a result for every meaning of an ambiguous query, e.g. "mercury" as a planet, an element and
+//	a god.  The query is made as it is, then again with each alternative value of its first Clash assumption applied,
+//	BatchConcurrency at a time.   The results are in the order of the values of the assumption, the first being the
+//	meaning Wolfram Alpha assumed; there is only that result if the query has no Clash assumption.  The first error is
+//	returned without results.
+func (c *Client) QueryAllAssumptions(ctx context.Context, query string, params url.Values) ([]*QueryResult, error) {
+	result, err := c.getQueryResult(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var clash *Assumption
+	for i := range result.Assumptions.Assumption {
+		if result.Assumptions.Assumption[i].Type == Clash {
+			clash = &result.Assumptions.Assumption[i]
+			break
+		}
+	}
+	if clash == nil || len(clash.Values) < 2 {
+		return []*QueryResult{result}, nil
+	}
+
+	alternatives := clash.Values[1:]
+	alternativeResults, errs := c.runBatch(ctx, len(alternatives), func(i int) (*QueryResult, error) {
+		return c.getQueryResult(ctx, query, params, "assumption="+EncodeAssumption(alternatives[i].Input))
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error querying assumption %s", alternatives[i].Name)
+		}
+	}
+	return append([]*QueryResult{result}, alternativeResults...), nil
+}
+
+// EncodeAssumption encodes an assumption input (the Input of a Value) for use as the value of the assumption parameter
+//	in a query string, e.g. "*C.dow+chemical-_*Company-".   All but the unreserved characters, '*' and ':' are percent
+//	encoded; in particular '+' is encoded as %2B so that it is not read as a space.  This is equivalent to the encoding
+//	of url.Values (as used by WithAssumption and MultiClashParams), which Wolfram Alpha decodes to the same input.
+func EncodeAssumption(input string) string {
+	return encodeInputValue(input)
+}
+
+// EncodePodState encodes a pod state input (the Input of a State) for use as the value of the podstate parameter in a
+//	query string, in the same way as EncodeAssumption.  Spaces, as in "Result__Step-by-step solution", are encoded as
+//	%20.
+func EncodePodState(input string) string {
+	return encodeInputValue(input)
+}
+
+// encodeInputValue percent encodes all but the unreserved characters of RFC 3986, '*' and ':'.
+func encodeInputValue(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var encoded strings.Builder
+	encoded.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+			encoded.WriteByte(b)
+		case b == '-', b == '.', b == '_', b == '~', b == '*', b == ':':
+			encoded.WriteByte(b)
+		default:
+			encoded.WriteByte('%')
+			encoded.WriteByte(hex[b>>4])
+			encoded.WriteByte(hex[b&0x0f])
+		}
+	}
+	return encoded.String()
+}
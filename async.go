@@ -0,0 +1,174 @@
+package wolfram
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultAsyncPollInterval is the time between polls of an async pod when the client does not set AsyncPollInterval.
+const DefaultAsyncPollInterval = 500 * time.Millisecond
+
+// GetQueryResultAsync gets the query result with async=true, so that Wolfram Alpha returns the pods that are ready
+//	rather than blocking on slow computations.  Pods that were not ready have an Async URL and no subpods; use
+//	FetchAsyncPod or FetchAsyncPods to fill them in.
+func (c *Client) GetQueryResultAsync(query string, params url.Values) (*QueryResult, error) {
+	return c.getQueryResult(context.Background(), query, asyncParams(params))
+}
+
+// asyncParams returns a copy of params requesting the result asynchronously.
+func asyncParams(params url.Values) url.Values {
+	params = cloneParams(params)
+	params.Set("async", "true")
+	return params
+}
+
+// GetQueryResultStream gets the query result asynchronously (see GetQueryResultAsync), calling onPod with each pod as it
+//	becomes available so that rendering can start before slow pods are computed.  Pods that were ready are passed in
+//	the order of the response, then each pending pod once fetched from its Async URL.   onPod is called from the calling
+//	goroutine.  The complete result is returned; on an error fetching a pod the pods not yet fetched are left pending.
+func (c *Client) GetQueryResultStream(ctx context.Context, query string, params url.Values, onPod func(Pod)) (*QueryResult, error) {
+	result, err := c.getQueryResult(ctx, query, asyncParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []int
+	for i := range result.Pods {
+		if result.Pods[i].Async != "" {
+			pending = append(pending, i)
+			continue
+		}
+		onPod(result.Pods[i])
+	}
+
+	for _, i := range pending {
+		if err := c.FetchAsyncPod(ctx, &result.Pods[i]); err != nil {
+			return result, errors.WithMessagef(err, "error fetching async pod %s", result.Pods[i].ID)
+		}
+		onPod(result.Pods[i])
+	}
+	return result, nil
+}
+
+// FetchAsyncPods fetches each pod of the result that has an Async URL in turn (see FetchAsyncPod), replacing it in the
+//	result.   The first error is returned, leaving the remaining pods as they were.
+func (c *Client) FetchAsyncPods(ctx context.Context, result *QueryResult) error {
+	for i := range result.Pods {
+		if err := c.FetchAsyncPod(ctx, &result.Pods[i]); err != nil {
+			return errors.WithMessagef(err, "error fetching async pod %s", result.Pods[i].ID)
+		}
+	}
+	return nil
+}
+
+// FetchAsyncPod fetches the content of a pod from its Async URL, replacing the pod with the complete one.  As long as
+//	Wolfram Alpha has nothing for the pod yet the URL is polled every AsyncPollInterval, until the pod is ready, ctx is
+//	done or the client is closed (returning ErrClientClosed).   A pod without an Async URL is left as it is.
+func (c *Client) FetchAsyncPod(ctx context.Context, pod *Pod) error {
+	if pod.Async == "" {
+		return nil
+	}
+
+	end, err := c.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
+	asyncURL, err := url.Parse(pod.Async)
+	if err != nil {
+		return errors.WithMessage(err, "invalid async url")
+	}
+	query := asyncURL.Query()
+	query.Set("output", string(OutputJSON))
+	asyncURL.RawQuery = query.Encode()
+
+	// polling stops when the client is closed, so that Close does not wait on pods that may never be ready
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	closing := c.closingChannel()
+	go func() {
+		select {
+		case <-closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	interval := c.AsyncPollInterval
+	if interval <= 0 {
+		interval = DefaultAsyncPollInterval
+	}
+
+	for {
+		fetched, err := c.getAsyncPod(ctx, asyncURL.String())
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if fetched != nil {
+			*pod = *fetched
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			select {
+			case <-closing:
+				return ErrClientClosed
+			default:
+				return ctx.Err()
+			}
+		case <-timer.C:
+		}
+	}
+}
+
+// getAsyncPod makes a single request for an async pod, returning nil if it is not ready: the response is empty or the
+//	pod still has an async URL.   The pod may be returned alone or as the only pod of a query result.
+func (c *Client) getAsyncPod(ctx context.Context, asyncURL string) (*Pod, error) {
+	res, err := c.get(ctx, "async", asyncURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error in obtaining full async pod result")
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	raw := struct {
+		Result *struct {
+			Pods []Pod `json:"pods"`
+		} `json:"queryresult"`
+	}{}
+	if err := jsonLib.Unmarshal(body, &raw); err != nil {
+		return nil, errors.WithMessage(err, "unable to interpret async pod json")
+	}
+
+	var pod Pod
+	if raw.Result != nil {
+		if len(raw.Result.Pods) == 0 {
+			return nil, nil
+		}
+		pod = raw.Result.Pods[0]
+	} else if err := jsonLib.Unmarshal(body, &pod); err != nil {
+		return nil, errors.WithMessage(err, "unable to interpret async pod json")
+	}
+
+	if pod.Async != "" {
+		return nil, nil
+	}
+	return &pod, nil
+}
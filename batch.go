@@ -0,0 +1,60 @@
+package wolfram
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of queries of a QueryBatch made at once when the client does not set
+//	BatchConcurrency.
+const DefaultBatchConcurrency = 4
+
+// QueryBatch gets the results of independent queries concurrently, e.g. for the panels of a dashboard, with the same
+//	extra parameters for each.  At most BatchConcurrency queries are made at once, each waiting on the RateLimiter if
+//	the client has one.   The results and errors are in the order of the queries, with a nil result where the query
+//	failed.  Queries not yet started when ctx is done fail with its error.
+func (c *Client) QueryBatch(ctx context.Context, queries []string, params url.Values) ([]*QueryResult, []error) {
+	return c.runBatch(ctx, len(queries), func(i int) (*QueryResult, error) {
+		return c.getQueryResult(ctx, queries[i], params)
+	})
+}
+
+// runBatch calls query for each index from 0 to n, at most BatchConcurrency at once, returning the results and errors
+//	in index order.  Indexes not yet started when ctx is done fail with its error.
+func (c *Client) runBatch(ctx context.Context, n int, query func(i int) (*QueryResult, error)) ([]*QueryResult, []error) {
+	results := make([]*QueryResult, n)
+	errs := make([]error, n)
+
+	concurrency := c.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i], errs[i] = query(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,98 @@
+package wolfram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ConversationResult is a turn of a conversation with the conversational API.  The ConversationID, Host and SInput
+//	are needed to continue the conversation (see FollowUpConversation).
+type ConversationResult struct {
+	Result         string `json:"result"`         // the answer, as a sentence
+	ConversationID string `json:"conversationID"` // identifies the conversation for follow up queries
+	Host           string `json:"host"`           // the host to send follow up queries to, e.g. "www4b.wolframalpha.com"
+	SInput         string `json:"s"`              // the s parameter for follow up queries, empty if not needed
+	Error          string `json:"error"`          // the reason there is no answer, empty if there is one
+}
+
+// GetConversationalQuery starts a conversation with the query, returning the answer and what is needed to continue the
+//	conversation.  If Wolfram Alpha has no answer the error matches ErrNoAnswer.
+//
+// Can take extra parameters, e.g. `units=metric`
+//
+// The parameters can be found here https://products.wolframalpha.com/conversational-api/documentation
+func (c *Client) GetConversationalQuery(query string, params url.Values) (*ConversationResult, error) {
+	if err := checkReservedParams(params, "appid", "i", "conversationid", "s"); err != nil {
+		return nil, err
+	}
+	return c.conversation(context.Background(), c.baseURL()+"/v1/conversation.jsp", query, "", "", params)
+}
+
+// ContinueConversation sends a follow up query in the conversation with the given ID, to the host returned with it.
+//	Where the previous result has an SInput, use FollowUpConversation which sends it too.
+func (c *Client) ContinueConversation(conversationID, host, query string) (*ConversationResult, error) {
+	return c.FollowUpConversation(&ConversationResult{ConversationID: conversationID, Host: host}, query)
+}
+
+// FollowUpConversation sends a follow up query in the conversation of the previous result.
+func (c *Client) FollowUpConversation(previous *ConversationResult, query string) (*ConversationResult, error) {
+	if previous.ConversationID == "" || previous.Host == "" {
+		return nil, errors.New("conversation id and host are required to continue a conversation")
+	}
+
+	// the host is normally a bare host name, but may include the scheme (e.g. for a mock server)
+	host := strings.TrimSuffix(previous.Host, "/")
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return c.conversation(context.Background(), host+"/api/v1/conversation.jsp", query, previous.ConversationID, previous.SInput, nil)
+}
+
+// conversation makes a request of the conversational API at endpointURL.
+func (c *Client) conversation(ctx context.Context, endpointURL, query, conversationID, sInput string, params url.Values) (*ConversationResult, error) {
+	end, err := c.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	if err := c.checkAppID(ctx); err != nil {
+		return nil, err
+	}
+
+	conversationURL := fmt.Sprintf("%s?appid=%s&i=%s", endpointURL, c.appID(ctx), url.QueryEscape(query))
+	if conversationID != "" {
+		conversationURL += "&conversationid=" + url.QueryEscape(conversationID)
+	}
+	if sInput != "" {
+		conversationURL += "&s=" + url.QueryEscape(sInput)
+	}
+	if params != nil {
+		conversationURL += "&" + params.Encode()
+	}
+
+	res, err := c.get(ctx, "conversation", conversationURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error in obtaining full wolfram alpha conversation result")
+	}
+
+	result := &ConversationResult{}
+	if err := jsonLib.Unmarshal(body, result); err != nil {
+		return nil, errors.WithMessage(err, "unable to interpret wolfram alpha conversation json")
+	}
+	if result.Error != "" {
+		return nil, errors.WithMessage(ErrNoAnswer, result.Error)
+	}
+	return result, nil
+}
@@ -0,0 +1,148 @@
+package wolfram
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPlanRequired denotes that Wolfram Alpha refused a request because the feature asked for (e.g. step-by-step
+//	solutions or certain formats) is not available on the API plan of the AppID.  Use errors.Is to test for it, and
+//	NeedsPlanUpgrade to obtain the feature name.
+var ErrPlanRequired = errors.New("feature requires an upgraded Wolfram Alpha API plan")
+
+// PlanError is the error returned when a plan-gated feature was requested.   It matches ErrPlanRequired with errors.Is.
+type PlanError struct {
+	Feature string // the feature refused, as named by Wolfram Alpha (e.g. "Step-by-step solution")
+	Code    string // the error code reported by Wolfram Alpha
+	Msg     string // the full error message reported by Wolfram Alpha
+}
+
+func (e *PlanError) Error() string {
+	return fmt.Sprintf("%s requires an upgraded Wolfram Alpha API plan, %s (code %s)", e.Feature, e.Msg, e.Code)
+}
+
+// Is reports a PlanError as ErrPlanRequired.
+func (e *PlanError) Is(target error) bool {
+	return target == ErrPlanRequired
+}
+
+// NeedsPlanUpgrade reports whether err is due to a feature not available on the API plan of the AppID, and if so the
+//	name of the feature so that the user can be told what an upgrade would give them.
+func NeedsPlanUpgrade(err error) (string, bool) {
+	var planErr *PlanError
+	if errors.As(err, &planErr) {
+		return planErr.Feature, true
+	}
+	return "", false
+}
+
+// planErrorPatterns match the messages Wolfram Alpha uses when a feature is not part of the plan.  The first group
+//	captures the feature name.
+var planErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^\s*(.+?)\s+(?:is|are)\s+(?:not available|unavailable|not supported|not enabled)\b.*\b(?:plan|subscription)\b`),
+	regexp.MustCompile(`(?i)^\s*(.+?)\s+requires?\s+an?\s+.*\b(?:plan|subscription)\b`),
+}
+
+// planRestrictedFeature returns the feature named in a plan-gated error message, ok is false if msg is not a plan error.
+func planRestrictedFeature(msg string) (string, bool) {
+	for _, pattern := range planErrorPatterns {
+		if match := pattern.FindStringSubmatch(msg); match != nil {
+			return match[1], true
+		}
+	}
+	return "", false
+}
+
+// ErrClientClosed is returned by requests made on a Client after it has been closed.
+var ErrClientClosed = errors.New("wolfram alpha client is closed")
+
+// ErrNoAnswer is returned by the answer helpers when Wolfram Alpha has no answer for the query.
+var ErrNoAnswer = errors.New("wolfram alpha has no answer for the query")
+
+// ErrNothingToRecalculate is returned by Recalculate for a result with no ReCalculate URL, i.e. no pods timed out.
+var ErrNothingToRecalculate = errors.New("wolfram alpha result has nothing to recalculate")
+
+// ErrReservedParam is returned when the extra parameters of a request include one set by the client itself, such as
+//	the input or appid, which would otherwise be sent twice.
+var ErrReservedParam = errors.New("parameter is reserved for use by the client")
+
+// ErrNoShortAnswer is matched by the error of the short and spoken answer endpoints when Wolfram Alpha has no short
+//	answer for the query (status 501), rather than returning its message as if it were the answer.
+var ErrNoShortAnswer = errors.New("wolfram alpha has no short answer for the query")
+
+// ErrInvalidAppID is matched by the error of a request refused because the AppID is invalid or missing, e.g. "Error 1:
+//	Invalid appid".
+var ErrInvalidAppID = errors.New("wolfram alpha appid is invalid")
+
+// ErrMissingAppID is returned by requests made by a Client without an AppID, rather than making a request that can only
+//	be refused.
+var ErrMissingAppID = errors.New("wolfram alpha appid is missing")
+
+// maxErrorBodySize limits how much of the body of an error response is kept in an APIError.
+const maxErrorBodySize = 64 * 1024
+
+// APIError is returned when Wolfram Alpha responds with a status other than 2xx, e.g. 403 for an invalid AppID or 501
+//	when the short answer endpoint has no answer.  Use errors.As to obtain it.
+type APIError struct {
+	Endpoint   string // the endpoint requested, e.g. "query", "simple", "result", "spoken" or "queryrecognizer"
+	StatusCode int    // the HTTP status code of the response
+	Body       string // the body of the response, which is usually a message from Wolfram Alpha
+	URL        string // the URL requested, with the AppID redacted
+
+	// RetryAfter is how long to wait before trying again as given by the Retry-After header, e.g. with 429 Too Many
+	//	Requests.   Zero if the header was not given.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("wolfram alpha %s request failed with status %d", e.Endpoint, e.StatusCode)
+	}
+	return fmt.Sprintf("wolfram alpha %s request failed with status %d, %s", e.Endpoint, e.StatusCode, e.Body)
+}
+
+// Is reports an APIError as ErrNoShortAnswer when an answer endpoint had no answer, and as ErrInvalidAppID when the
+//	AppID was refused.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNoShortAnswer:
+		return e.StatusCode == http.StatusNotImplemented && (e.Endpoint == "result" || e.Endpoint == "spoken")
+	case ErrInvalidAppID:
+		return (e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusUnauthorized) &&
+			strings.Contains(strings.ToLower(e.Body), "appid")
+	}
+	return false
+}
+
+// parseRetryAfter returns the time to wait given by a Retry-After header, which is either a number of seconds or an
+//	HTTP date.   Zero is returned if the header is empty, invalid or in the past.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// appIDPattern matches the appid parameter of a query string
+var appIDPattern = regexp.MustCompile(`(?i)([?&]appid=)[^&]*`)
+
+// redactAppID replaces the value of the appid parameter of a URL so that it is not leaked through errors or logs.
+func redactAppID(url string) string {
+	return appIDPattern.ReplaceAllString(url, "${1}REDACTED")
+}
@@ -0,0 +1,164 @@
+// Package fakes provides a programmable stand-in for the Wolfram Alpha client, for testing code that depends on
+//	wolfram.API without making requests.
+package fakes
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+
+	wolfram "wolframAlpha"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotStubbed is returned by a method of Client that has no function set for it.
+var ErrNotStubbed = errors.New("fake wolfram alpha client method is not stubbed")
+
+// Call is a call of a method of Client, recorded in the order made.
+type Call struct {
+	Method string // the name of the method, e.g. "GetQueryResult"
+	Query  string // the query given, empty for methods without one
+}
+
+// Client implements wolfram.API by calling the function set for each method, e.g.
+//
+//	fake := &fakes.Client{
+//		GetShortAnswerQueryFunc: func(query string, units wolfram.Unit, timeout int) (string, error) {
+//			return "2", nil
+//		},
+//	}
+//
+//	Methods without a function return ErrNotStubbed.   Every call is recorded (see Calls), and a Client is safe for
+//	concurrent use if its functions are.
+type Client struct {
+	GetQueryResultFunc               func(query string, params url.Values) (*wolfram.QueryResult, error)
+	GetQueryResultWithAssumptionFunc func(query string, assumptionInput string, params url.Values) (*wolfram.QueryResult, error)
+	GetQueryResultWithPodStatesFunc  func(query string, podStates []string, params url.Values) (*wolfram.QueryResult, error)
+	GetFullResultFunc                func(ctx context.Context, query string, params url.Values) (*wolfram.QueryResult, error)
+	GetAnswerFunc                    func(ctx context.Context, query string, params url.Values) (string, error)
+	GetShortAnswerQueryFunc          func(query string, units wolfram.Unit, timeout int) (string, error)
+	GetSpokenAnswerQueryFunc         func(query string, units wolfram.Unit, timeout int) (string, error)
+	GetSimpleQueryFunc               func(query string, params url.Values) (io.ReadCloser, string, error)
+	GetLLMQueryFunc                  func(query string, params url.Values) (string, error)
+	GetFastQueryRecognizerFunc       func(query string, mode wolfram.Mode, params ...url.Values) (*wolfram.FastQueryResult, error)
+	GetValidateQueryFunc             func(query string, params url.Values) (*wolfram.ValidationResult, error)
+	GetConversationalQueryFunc       func(query string, params url.Values) (*wolfram.ConversationResult, error)
+	FollowUpConversationFunc         func(previous *wolfram.ConversationResult, query string) (*wolfram.ConversationResult, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ wolfram.API = (*Client)(nil)
+
+// Calls returns the calls made of the client so far, in order.
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// record records a call of the method, returning an error matching ErrNotStubbed if it has no function.
+func (c *Client) record(method string, query string, stubbed bool) error {
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{Method: method, Query: query})
+	c.mu.Unlock()
+
+	if !stubbed {
+		return errors.WithMessage(ErrNotStubbed, method)
+	}
+	return nil
+}
+
+func (c *Client) GetQueryResult(query string, params url.Values) (*wolfram.QueryResult, error) {
+	if err := c.record("GetQueryResult", query, c.GetQueryResultFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetQueryResultFunc(query, params)
+}
+
+func (c *Client) GetQueryResultWithAssumption(query string, assumptionInput string, params url.Values) (*wolfram.QueryResult, error) {
+	if err := c.record("GetQueryResultWithAssumption", query, c.GetQueryResultWithAssumptionFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetQueryResultWithAssumptionFunc(query, assumptionInput, params)
+}
+
+func (c *Client) GetQueryResultWithPodStates(query string, podStates []string, params url.Values) (*wolfram.QueryResult, error) {
+	if err := c.record("GetQueryResultWithPodStates", query, c.GetQueryResultWithPodStatesFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetQueryResultWithPodStatesFunc(query, podStates, params)
+}
+
+func (c *Client) GetFullResult(ctx context.Context, query string, params url.Values) (*wolfram.QueryResult, error) {
+	if err := c.record("GetFullResult", query, c.GetFullResultFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetFullResultFunc(ctx, query, params)
+}
+
+func (c *Client) GetAnswer(ctx context.Context, query string, params url.Values) (string, error) {
+	if err := c.record("GetAnswer", query, c.GetAnswerFunc != nil); err != nil {
+		return "", err
+	}
+	return c.GetAnswerFunc(ctx, query, params)
+}
+
+func (c *Client) GetShortAnswerQuery(query string, units wolfram.Unit, timeout int) (string, error) {
+	if err := c.record("GetShortAnswerQuery", query, c.GetShortAnswerQueryFunc != nil); err != nil {
+		return "", err
+	}
+	return c.GetShortAnswerQueryFunc(query, units, timeout)
+}
+
+func (c *Client) GetSpokenAnswerQuery(query string, units wolfram.Unit, timeout int) (string, error) {
+	if err := c.record("GetSpokenAnswerQuery", query, c.GetSpokenAnswerQueryFunc != nil); err != nil {
+		return "", err
+	}
+	return c.GetSpokenAnswerQueryFunc(query, units, timeout)
+}
+
+func (c *Client) GetSimpleQuery(query string, params url.Values) (io.ReadCloser, string, error) {
+	if err := c.record("GetSimpleQuery", query, c.GetSimpleQueryFunc != nil); err != nil {
+		return nil, "", err
+	}
+	return c.GetSimpleQueryFunc(query, params)
+}
+
+func (c *Client) GetLLMQuery(query string, params url.Values) (string, error) {
+	if err := c.record("GetLLMQuery", query, c.GetLLMQueryFunc != nil); err != nil {
+		return "", err
+	}
+	return c.GetLLMQueryFunc(query, params)
+}
+
+func (c *Client) GetFastQueryRecognizer(query string, mode wolfram.Mode, params ...url.Values) (*wolfram.FastQueryResult, error) {
+	if err := c.record("GetFastQueryRecognizer", query, c.GetFastQueryRecognizerFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetFastQueryRecognizerFunc(query, mode, params...)
+}
+
+func (c *Client) GetValidateQuery(query string, params url.Values) (*wolfram.ValidationResult, error) {
+	if err := c.record("GetValidateQuery", query, c.GetValidateQueryFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetValidateQueryFunc(query, params)
+}
+
+func (c *Client) GetConversationalQuery(query string, params url.Values) (*wolfram.ConversationResult, error) {
+	if err := c.record("GetConversationalQuery", query, c.GetConversationalQueryFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.GetConversationalQueryFunc(query, params)
+}
+
+func (c *Client) FollowUpConversation(previous *wolfram.ConversationResult, query string) (*wolfram.ConversationResult, error) {
+	if err := c.record("FollowUpConversation", query, c.FollowUpConversationFunc != nil); err != nil {
+		return nil, err
+	}
+	return c.FollowUpConversationFunc(previous, query)
+}
@@ -0,0 +1,113 @@
+package wolfram
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// currencySymbols maps the currency symbols used in financial answers to ISO 4217 codes.  Longer symbols come first so
+//	that e.g. "US$" is not read as "$".
+var currencySymbols = []struct {
+	symbol string
+	code   string
+}{
+	{"US$", "USD"},
+	{"CA$", "CAD"},
+	{"A$", "AUD"},
+	{"$", "USD"},
+	{"€", "EUR"},
+	{"£", "GBP"},
+	{"¥", "JPY"},
+	{"₹", "INR"},
+	{"₩", "KRW"},
+	{"₽", "RUB"},
+	{"CHF", "CHF"},
+}
+
+var (
+	// amountBySymbol matches an amount preceded by a currency symbol, e.g. "$1,234.56"
+	amountBySymbol = func() *regexp.Regexp {
+		symbols := make([]string, len(currencySymbols))
+		for i, currency := range currencySymbols {
+			symbols[i] = regexp.QuoteMeta(currency.symbol)
+		}
+		return regexp.MustCompile(`(` + strings.Join(symbols, "|") + `)\s*(-?\d[\d,]*(?:\.\d+)?)`)
+	}()
+
+	// amountByCode matches an amount followed by an ISO currency code, e.g. "1,234.56 GBP"
+	amountByCode = regexp.MustCompile(`(-?\d[\d,]*(?:\.\d+)?)\s*([A-Z]{3})\b`)
+
+	// parenthesised matches the parenthesised annotations of an answer, which hold the date of a price
+	parenthesised = regexp.MustCompile(`\(([^()]*)\)`)
+)
+
+// financialDateLayouts are the date formats seen in the annotations of financial answers.
+var financialDateLayouts = []string{
+	"Monday, January 2, 2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"2006-01-02",
+	"01/02/2006",
+	"January 2006",
+}
+
+// FinancialResult interprets the primary answer of a financial query, such as a stock or commodity price, in the common
+//	forms "$1,234.56 (as of ...)" or "1,234.56 GBP per ...".   It returns the amount, the ISO 4217 code of the currency
+//	and the date of the price, which is the zero time if the answer is not dated.   ok is false if the answer does not
+//	hold a price.
+func (result *QueryResult) FinancialResult() (float64, string, time.Time, bool) {
+	answer, found := result.FirstAnswer()
+	if !found {
+		return 0, "", time.Time{}, false
+	}
+
+	amount, currency, ok := parseFinancialAmount(answer)
+	if !ok {
+		return 0, "", time.Time{}, false
+	}
+	return amount, currency, parseFinancialDate(answer), true
+}
+
+// parseFinancialAmount returns the first amount in the text with its currency code.
+func parseFinancialAmount(text string) (float64, string, bool) {
+	if match := amountBySymbol.FindStringSubmatch(text); match != nil {
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(match[2], ",", ""), 64)
+		if err == nil {
+			for _, currency := range currencySymbols {
+				if currency.symbol == match[1] {
+					return amount, currency.code, true
+				}
+			}
+		}
+	}
+	if match := amountByCode.FindStringSubmatch(text); match != nil {
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err == nil {
+			return amount, match[2], true
+		}
+	}
+	return 0, "", false
+}
+
+// parseFinancialDate returns the date in the parenthesised annotations of the text, e.g. "(as of October 16, 2026)" or
+//	"(DOW | NYSE | Friday, October 16, 2026)", or the zero time if there is none.
+func parseFinancialDate(text string) time.Time {
+	for _, annotation := range parenthesised.FindAllStringSubmatch(text, -1) {
+		for _, part := range strings.Split(annotation[1], "|") {
+			part = strings.TrimSpace(part)
+			if len(part) > 5 && strings.EqualFold(part[:5], "as of") {
+				part = strings.TrimSpace(part[5:])
+			}
+			for _, layout := range financialDateLayouts {
+				if date, err := time.Parse(layout, part); err == nil {
+					return date
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
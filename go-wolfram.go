@@ -2,22 +2,32 @@ package wolfram
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/pkg/errors"
-	"github.com/valyala/fasttemplate"
 
 	jsonIter "github.com/json-iterator/go"
 )
 
 var jsonLib = jsonIter.ConfigCompatibleWithStandardLibrary
 
+// Version is the version of this library, sent in the default User-Agent.
+const Version = "0.1.0"
+
+// DefaultUserAgent is the User-Agent header sent with every request unless the client sets UserAgent.
+const DefaultUserAgent = "go-wolfram/" + Version
+
 /*
 	See the following for detail on the API:
 
@@ -25,8 +35,66 @@ var jsonLib = jsonIter.ConfigCompatibleWithStandardLibrary
 */
 
 // Client requires an App ID, which you can sign up for at https://developer.wolframalpha.com/
+//
+// A Client may be created with NewClient and options, or as a struct literal (wolfram.Client{AppID: ...}).   The zero
+//	value of every other field is a working default.
 type Client struct {
+	// AppID is the AppID of every request, unless one is given by the context of the request (see ContextWithAppID).
 	AppID string
+
+	// HTTPClient is used for every request, e.g. to set a timeout, proxy or transport.  http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+
+	// UserAgent is sent as the User-Agent header of every request, DefaultUserAgent if empty.
+	UserAgent string
+
+	// RateLimiter, when set, is waited on before every request so that bursts of requests do not exceed the quota of the
+	//	AppID.  Requests are not limited if nil.
+	RateLimiter RateLimiter
+
+	// Logger receives debug output, such as the JSON of each full result, when set.  Note that this includes the content
+	//	of queries.  Nothing is logged if nil.
+	Logger Logger
+
+	// OnDecodeError, when set, is called for each field of a full result that fails to decode (the field name and the
+	//	error).  The field is skipped and the rest of the result returned rather than failing the whole request, which
+	//	allows drift in the API to be tracked without breaking callers.
+	OnDecodeError func(field string, err error)
+
+	// BaseURL is the base URL of the api.wolframalpha.com host, used by all but the fast query recognizer.  Empty uses
+	//	the real host; it may be set to e.g. a mock server when testing.
+	BaseURL string
+
+	// WebBaseURL is the base URL of the www.wolframalpha.com host, used by the fast query recognizer and LLM API.
+	//	Empty uses the real host; it may be set to e.g. a mock server when testing.
+	WebBaseURL string
+
+	// SortPodsOnDecode sorts the pods of each full result by position once decoded (see QueryResult.SortPods).
+	SortPodsOnDecode bool
+
+	// BatchConcurrency is the number of queries of a QueryBatch made at once.  Zero uses DefaultBatchConcurrency.
+	BatchConcurrency int
+
+	// AsyncPollInterval is the time between polls of the async URL of a pod that is not yet ready (see FetchAsyncPod).
+	//	Zero uses DefaultAsyncPollInterval.
+	AsyncPollInterval time.Duration
+
+	// RequestTimeout, when set, limits the time taken by each request, including reading the response.  It applies on
+	//	top of the context of the request, the earlier deadline taking effect.  Requests are not limited if zero.
+	RequestTimeout time.Duration
+
+	lifecycle *lifecycle
+}
+
+// Logger is the interface for debug output from the client, which *log.Logger satisfies.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// RateLimiter is the interface for limiting the rate of requests from the client, which *rate.Limiter of
+//	golang.org/x/time/rate satisfies.   Wait blocks until a request may be made, returning an error if ctx is done first.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
 }
 
 type Query struct {
@@ -35,8 +103,13 @@ type Query struct {
 
 //The QueryResult is what you get back after a request
 type QueryResult struct {
+	//The query as it was given to the client, e.g. "1 + 1 = ?".  This previously held the query escaped for the request
+	//URL ("1+%2B+1+%3D+%3F"); use url.QueryEscape for that form.
 	Query string
 
+	//The pod states the query was made with, in the order applied (see GetQueryResultWithPodStates)
+	PodStates []string `json:"-"`
+
 	//The pods are what hold the majority of the information
 	Pods []Pod `json:"pods"`
 
@@ -46,13 +119,12 @@ type QueryResult struct {
 	//Assumptions show info if some assumption was made while parsing the query
 	Assumptions Assumptions `json:"assumptions"`
 
-	// Each Source contains a link to a web page with the source information.
-	//  johnha: removed this from interpretation.  This is a single object when only 1, otherwise an array.   We need 1 or
-	//	the other to unmarshall so will ignore.
-	// Sources []Source `json:"sources"`
+	// Each Source contains a link to a web page with the source information.  This is a single object when only 1,
+	//	otherwise an array (see Sources.UnmarshalJSON).
+	Sources Sources `json:"sources"`
 
-	//Generalizes the query to display more information
-	Generalizations []Generalization `json:"generalization"`
+	//Generalizes the query to display more information.  This is a single object when only 1, otherwise an array
+	Generalizations Generalizations `json:"generalization"`
 
 	//true or false depending on whether the input could be successfully
 	//understood. If false there will be no <pod> subelements
@@ -61,6 +133,9 @@ type QueryResult struct {
 	// If no error, this will be false.  If an error, this is an object with a code and description
 	Error QueryError `json:"error"`
 
+	// The code and message of the error reported by Wolfram Alpha, nil if no error
+	ErrorDetail *Error `json:"-"`
+
 	//The number of pod elements
 	NumPods int `json:"numpods"`
 
@@ -94,6 +169,112 @@ type QueryResult struct {
 	Version string `json:"version"`
 }
 
+// resultFields is an alias of QueryResult, decoded by the standard unmarshalling rather than UnmarshalJSON.
+type resultFields QueryResult
+
+// UnmarshalJSON for the query result, filling in ErrorDetail from the error.
+func (result *QueryResult) UnmarshalJSON(data []byte) error {
+	if err := jsonLib.Unmarshal(data, (*resultFields)(result)); err != nil {
+		return err
+	}
+	result.ErrorDetail = result.Error.detail()
+	return nil
+}
+
+// SortPods stably sorts the pods by their position.  Wolfram Alpha usually returns pods in position order, but this
+//	guarantees a deterministic display order, e.g. once pods from other requests have been merged in.
+func (result *QueryResult) SortPods() {
+	sort.SliceStable(result.Pods, func(i, j int) bool {
+		return result.Pods[i].Position < result.Pods[j].Position
+	})
+}
+
+// PrimaryPods returns the pods marked as primary, i.e. those Wolfram Alpha considers the headline result.  This is
+//	usually a single pod, and empty if no pod is marked.
+func (result *QueryResult) PrimaryPods() []Pod {
+	var pods []Pod
+	for _, pod := range result.Pods {
+		if pod.Primary {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// PodByID returns the pod with the given ID, e.g. "Result".  ok is false if there is no such pod.
+func (result *QueryResult) PodByID(id string) (*Pod, bool) {
+	for i := range result.Pods {
+		if result.Pods[i].ID == id {
+			return &result.Pods[i], true
+		}
+	}
+	return nil, false
+}
+
+// PodByTitle returns the pod with the given title, ignoring case and surrounding whitespace.  ok is false if there is
+//	no such pod.
+func (result *QueryResult) PodByTitle(title string) (*Pod, bool) {
+	title = strings.TrimSpace(title)
+	for i := range result.Pods {
+		if strings.EqualFold(strings.TrimSpace(result.Pods[i].Title), title) {
+			return &result.Pods[i], true
+		}
+	}
+	return nil, false
+}
+
+// PodsByScanner returns the pods produced by the given scanner, e.g. "Data", ignoring case, in the order of the result.
+func (result *QueryResult) PodsByScanner(scanner string) []Pod {
+	var pods []Pod
+	for _, pod := range result.Pods {
+		if strings.EqualFold(pod.Scanner, scanner) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// Scanners returns the names of the scanners that produced the pods of the result, each once in the order first seen.
+//	Names differing only in case are the same scanner.
+func (result *QueryResult) Scanners() []string {
+	var scanners []string
+	seen := map[string]bool{}
+	for _, pod := range result.Pods {
+		key := strings.ToLower(pod.Scanner)
+		if pod.Scanner == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		scanners = append(scanners, pod.Scanner)
+	}
+	return scanners
+}
+
+// TimingDuration returns Timing, the wall-clock time taken to generate the result, as a duration.
+func (result *QueryResult) TimingDuration() time.Duration {
+	return secondsDuration(result.Timing)
+}
+
+// ParseTimingDuration returns ParseTiming, the time taken to parse the query, as a duration.
+func (result *QueryResult) ParseTimingDuration() time.Duration {
+	return secondsDuration(result.ParseTiming)
+}
+
+// secondsDuration converts a time in seconds as reported by Wolfram Alpha to a duration, rounded to the microsecond.
+func secondsDuration(seconds float64) time.Duration {
+	return (time.Duration(seconds*float64(time.Second)) + time.Microsecond/2).Truncate(time.Microsecond)
+}
+
+// Generalizations are the generalizations of a query, given by Wolfram Alpha as an object when there is only one.
+type Generalizations []Generalization
+
+func (g *Generalizations) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObjectOrArray(data, (*[]Generalization)(g)); err != nil {
+		return errors.WithMessage(err, "error interpreting generalizations")
+	}
+	return nil
+}
+
 type Generalization struct {
 	Topic       string `json:"topic"`
 	Description string `json:"desc"`
@@ -105,7 +286,7 @@ type Warnings struct {
 	Count int `json:"count"`
 
 	//Suggestions for spelling corrections
-	Spellchecks []Spellcheck `json:"spellcheck"`
+	Spellchecks Spellchecks `json:"spellcheck"`
 
 	//"If you enter a query with mismatched delimiters like "sin(x", Wolfram|Alpha attempts to fix the problem and reports
 	//this as a warning."
@@ -117,7 +298,17 @@ type Warnings struct {
 
 	//"[The API] can automatically try to reinterpret a query that it does not understand but that seems close to one
 	//that it can."
-	ReInterpretations []ReInterpretation `json:"reinterpret"`
+	ReInterpretations ReInterpretations `json:"reinterpret"`
+}
+
+// Spellchecks are the spelling warnings of a result, given by Wolfram Alpha as an object when there is only one.
+type Spellchecks []Spellcheck
+
+func (s *Spellchecks) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObjectOrArray(data, (*[]Spellcheck)(s)); err != nil {
+		return errors.WithMessage(err, "error interpreting spellchecks")
+	}
+	return nil
 }
 
 type Spellcheck struct {
@@ -137,23 +328,115 @@ type Translation struct {
 	Text        string `json:"text"`
 }
 
+// ReInterpretations are the reinterpretations of a result, given by Wolfram Alpha as an object when there is only one.
+type ReInterpretations []ReInterpretation
+
+func (r *ReInterpretations) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObjectOrArray(data, (*[]ReInterpretation)(r)); err != nil {
+		return errors.WithMessage(err, "error interpreting reinterpretations")
+	}
+	return nil
+}
+
 type ReInterpretation struct {
-	Alternatives []Alternative `json:"alternative"`
-	Text         string        `json:"text"`
-	New          string        `json:"new"`
+	Alternatives Alternatives `json:"alternative"`
+	Text         string       `json:"text"`
+	New          string       `json:"new"`
+}
+
+// Alternatives are the other interpretations considered, given by Wolfram Alpha as an object when there is only one.
+type Alternatives []Alternative
+
+func (a *Alternatives) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObjectOrArray(data, (*[]Alternative)(a)); err != nil {
+		return errors.WithMessage(err, "error interpreting alternatives")
+	}
+	return nil
 }
 
+// Alternative is another interpretation of the query that Wolfram Alpha considered, with how well it matched.
 type Alternative struct {
-	InnerText string `json:",innerxml"`
+	Value string `json:"val"`
+	Score string `json:"score"` // e.g. "0.385429"
+	Level string `json:"level"` // e.g. "medium"
+}
+
+// UnmarshalJSON accepts an alternative given as just its value as well as an object.
+func (a *Alternative) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*a = Alternative{Value: value}
+		return nil
+	}
+
+	type alternativeFields Alternative
+	if err := json.Unmarshal(data, (*alternativeFields)(a)); err != nil {
+		return errors.WithMessage(err, "error interpreting alternative")
+	}
+	return nil
 }
 
 // QueryError denotes an error returned by the server.  Note that Wolfram returns a boolean if no error, and a structure
 //	containing description and code if an error.  We therefore need to marshall appropriately.
 type QueryError struct {
-	Err error // nil if no error, a message if error
+	Err  error  // nil if no error, a message if error
+	Code string // the code reported by Wolfram Alpha, empty if no error
+	Msg  string // the message reported by Wolfram Alpha, empty if no error
+}
+
+// detail returns the code and message of the error, nil if there is no error.
+func (qe *QueryError) detail() *Error {
+	if qe.Err == nil {
+		return nil
+	}
+	code, _ := strconv.Atoi(qe.Code)
+	return &Error{Code: code, Msg: qe.Msg}
+}
+
+// Error is the code and message of an error reported by Wolfram Alpha for a query or pod, e.g. code 1 for an invalid
+//	appid.
+type Error struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// UnmarshalJSON for an error.   The code is a string in the json of query results, so both a string and a number are
+//	accepted.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	reportedError := struct {
+		Code json.RawMessage `json:"code"`
+		Msg  string          `json:"msg"`
+	}{}
+	if err := json.Unmarshal(data, &reportedError); err != nil {
+		return errors.WithMessage(err, "unable to interpret error")
+	}
+
+	e.Code = 0
+	e.Msg = reportedError.Msg
+	if code := strings.Trim(string(reportedError.Code), `"`); code != "" && code != "null" {
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			return errors.Errorf("error code is not a number (%s)", code)
+		}
+		e.Code = n
+	}
+	return nil
+}
+
+// MarshalJSON writes the error as Wolfram Alpha does, false if there is no error, otherwise the code and message, so
+//	that a result can be encoded and decoded again.
+func (qe QueryError) MarshalJSON() ([]byte, error) {
+	if qe.Err == nil {
+		return []byte("false"), nil
+	}
+	return json.Marshal(struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{Code: qe.Code, Msg: qe.Msg})
 }
 
 func (qe *QueryError) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
 	if len(data) == 0 {
 		return errors.New("no bytes in error to unmarshall")
 	}
@@ -170,14 +453,22 @@ func (qe *QueryError) UnmarshalJSON(data []byte) error {
 		if err := json.Unmarshal(data, &reportedError); err != nil {
 			return errors.WithMessage(err, "unable to interpret error response")
 		}
-		qe.Err = errors.Errorf("error in Wolfram Alpha request, %s (code %s)", reportedError.Msg, reportedError.Code)
+		qe.Code = reportedError.Code
+		qe.Msg = reportedError.Msg
+		if feature, ok := planRestrictedFeature(reportedError.Msg); ok {
+			qe.Err = &PlanError{Feature: feature, Code: reportedError.Code, Msg: reportedError.Msg}
+		} else {
+			qe.Err = errors.Errorf("error in Wolfram Alpha request, %s (code %s)", reportedError.Msg, reportedError.Code)
+		}
 
 	default:
 		// otherwise this expected to be text true/false.  I would assume always true if not an object, but will check and report
-		if string(data) == "false" {
+		if string(data) == "false" || string(data) == "null" {
 			qe.Err = nil
+			qe.Code = ""
+			qe.Msg = ""
 		} else {
-			qe.Err = errors.Errorf("Wolfram Alpha reported failure with no error detail (%d)", string(data))
+			qe.Err = errors.Errorf("Wolfram Alpha reported failure with no error detail (%s)", string(data))
 		}
 	}
 	return nil
@@ -215,28 +506,43 @@ type Assumptions struct {
 // UnmarshalJSON for assumptions.   Issue with the response from WA in that if a single assumption then an object is returned
 //	containing the single assumption detail.   This appears to be a configuration with Ajax java library.   Questionable design
 //	but we work around by performing the check.
+//	A wrapper without an 'assumption' entry, e.g. {"count":0}, has no assumptions.
 //  see: https://www.calhoun.io/how-to-parse-json-that-varies-between-an-array-or-a-single-item-with-go/
 func (a *Assumptions) UnmarshalJSON(data []byte) error {
 
-	if len(data) == 0 {
-		return errors.New("no bytes in assumptions to unmarshall")
+	// assumptions are optional, so null or nothing at all means there are none
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		a.Assumption = nil
+		a.Count = 0
+		return nil
 	}
 
-	fmt.Printf("\n\n^^^^^^^^\n ******* \nassumptions: %s\n\n", string(data))
-
-	// determine whether object or array and unmarshall appropriately.   Note that go json unmarshaller should have removed
-	//	the leading spaces and this should be ok (will fail otherwise).
+	// determine whether object or array and unmarshall appropriately.
 	switch data[0] {
 	case '{':
-		fmt.Printf("\n\nassumptions (object: %s\n\n", string(data))
+		// the assumptions may be wrapped as in the xml (and as Assumptions is encoded), {"count":2,"assumption":[...]}
+		wrapper := struct {
+			Count      int             `json:"count"`
+			Type       *string         `json:"type"`
+			Assumption json.RawMessage `json:"assumption"`
+		}{}
+		if err := json.Unmarshal(data, &wrapper); err == nil && len(wrapper.Assumption) > 0 {
+			return a.UnmarshalJSON(wrapper.Assumption)
+		} else if err == nil && wrapper.Type == nil {
+			// a single assumption always has a type (and its own count, of its values), so an object without one is a
+			//	wrapper without entries, e.g. {"count":0}
+			a.Assumption = nil
+			a.Count = wrapper.Count
+			return nil
+		}
+
 		// unmarshal single assumption
 		a.Count = 1
 		a.Assumption = make([]Assumption, 1)
 		return json.Unmarshal(data, &a.Assumption[0])
 
 	case '[':
-		fmt.Printf("\n\nassumptions (array: %s\n\n", string(data))
-
 		if err := jsonLib.Unmarshal(data, &a.Assumption); err != nil {
 			return errors.WithMessage(err, "error interpreting assumption")
 		} else {
@@ -250,11 +556,11 @@ func (a *Assumptions) UnmarshalJSON(data []byte) error {
 }
 
 type Assumption struct {
-	Values   []Value `json:"values"`   // alternate values and actions to refine on request
-	Type     string  `json:"type"`     // classification of an assumption that defines how it will function
-	Word     string  `json:"word"`     // the central word/phrase to which the assumption is applied
-	Template string  `json:"template"` // statement outlining the way an assumption will be applied
-	Count    int     `json:"count"`
+	Values   ValueList `json:"values"`   // alternate values and actions to refine on request
+	Type     string    `json:"type"`     // classification of an assumption that defines how it will function
+	Word     string    `json:"word"`     // the central word/phrase to which the assumption is applied
+	Template string    `json:"template"` // statement outlining the way an assumption will be applied
+	Count    int       `json:"count"`
 }
 
 /* ForActionDisplay will return a display representation of the assumption with associated action.
@@ -292,30 +598,34 @@ type ActionAssumption struct {
 	Description string // description (e.g. as Movie)
 }
 
-// ForActionDisplay will return a display representation of the assumption with associated action.
+// ForActionDisplay will return a display representation of the assumption with associated action.   The template of
+//	the assumption numbers the words and descriptions of its values from 1, the first being the value assumed, e.g.
+//	"Assuming ${word1} is ${desc1}. Use \"${word2}\" as ${desc2} instead" for a MultiClash.   Templates naming only the
+//	second value are given each alternative in its place, while templates listing every alternative, as for a Unit
+//	assumption ("Assuming ${desc1} for \"${word}\". Use ${desc2} or ${desc3} instead"), give the same label for each.
 func (assumption *Assumption) ForActionDisplay() (*[]ActionAssumption, error) {
 	if len(assumption.Values) < 2 {
 		// the first element of the assumption list is the one applied.   There has to be >1 for it to be an assumption
 		return nil, errors.New("nothing to assume")
 	}
 	actions := make([]ActionAssumption, 0, len(assumption.Values)-1)
-	assumedValue := assumption.Values[0].Description
 
-	template := fasttemplate.New(assumption.Template, "${", "}")
+	values := map[string]string{"word": assumption.Word}
+	for i, value := range assumption.Values {
+		values[fmt.Sprintf("word%d", i+1)] = assumption.valueWord(value)
+		values[fmt.Sprintf("desc%d", i+1)] = value.Description
+	}
+	listsAlternatives := strings.Contains(assumption.Template, "${desc3}")
 
 	for _, value := range assumption.Values[1:] {
 		var displayAssumption ActionAssumption
-		// replace ${word} with assumption word, and ${desc1} and ${desc2}.   desc1 being the assumed value (first element)
-		//	and desc2 being the current proposed value.
-		label := template.ExecuteString(
-			map[string]interface{}{
-				"word":  assumption.Word,
-				"desc1": assumedValue,
-				"desc2": value.Description,
-			},
-		)
+		if !listsAlternatives {
+			// ${word2} and ${desc2} being the current proposed value
+			values["word2"] = assumption.valueWord(value)
+			values["desc2"] = value.Description
+		}
 
-		displayAssumption.Label = label
+		displayAssumption.Label = expandTemplate(assumption.Template, values)
 		displayAssumption.Action = value.Input
 		displayAssumption.ButtonLabel = value.Name
 		displayAssumption.Description = value.Description
@@ -325,11 +635,76 @@ func (assumption *Assumption) ForActionDisplay() (*[]ActionAssumption, error) {
 	return &actions, nil
 }
 
+// valueWord returns the word the value applies to, which is the word of the assumption unless the value names its own
+//	(as those of a MultiClash do).
+func (assumption *Assumption) valueWord(value Value) string {
+	if value.Word == "" {
+		return assumption.Word
+	}
+	return value.Word
+}
+
+// expandTemplate replaces each ${name} placeholder of an assumption template with its value, every occurrence being
+//	replaced.   Placeholders without a value are removed, and a "${" that is not closed is left as it is.
+func expandTemplate(template string, values map[string]string) string {
+	var expanded strings.Builder
+	for {
+		start := strings.Index(template, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(template[start+2:], "}")
+		if end < 0 {
+			break
+		}
+		expanded.WriteString(template[:start])
+		expanded.WriteString(values[template[start+2:start+2+end]])
+		template = template[start+2+end+1:]
+	}
+	expanded.WriteString(template)
+	return expanded.String()
+}
+
 // Value contains info about an assumption
 type Value struct {
 	Name        string `json:"name"`
 	Description string `json:"desc"`
 	Input       string `json:"input"`
+	Word        string `json:"word"` // the word the value applies to, only for MultiClash assumptions
+}
+
+// ValueList is the list of values of an assumption.
+type ValueList []Value
+
+// UnmarshalJSON for the values of an assumption.  As with assumptions themselves, a single value is returned as an object
+//	rather than an array holding the object, so both are accepted.
+func (v *ValueList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("no bytes in assumption values to unmarshall")
+	}
+
+	switch data[0] {
+	case '{':
+		var value Value
+		if err := json.Unmarshal(data, &value); err != nil {
+			return errors.WithMessage(err, "error interpreting assumption value")
+		}
+		*v = ValueList{value}
+
+	case '[':
+		if err := json.Unmarshal(data, (*[]Value)(v)); err != nil {
+			return errors.WithMessage(err, "error interpreting assumption values")
+		}
+
+	case 'n':
+		// null, no values
+		*v = nil
+
+	default:
+		return errors.Errorf("assumption values json does not indicate an object or array (%s)", string(data[0]))
+	}
+	return nil
 }
 
 // Pod elements are sub-elements of <queryresult>. Each contains the results for a single pod
@@ -337,7 +712,8 @@ type Pod struct {
 	//The subpod elements of the pod
 	SubPods []SubPod `json:"subpods"`
 
-	// Infos  []Info  `json:"infos"`	// johnha: api denotes a 'count' property, but missing in actual response (has object with property 'units' for example when looking up UK)
+	// Infos hold notes, links and unit legends for the pod, e.g. the unit abbreviations used by a currency conversion.
+	Infos Infos `json:"infos"`
 
 	// states will contain alternative states for the pod (shown as buttons on the wolfram detailed response).  An example is to request more population detail for example.   The state has
 	//	a name (that can be displayed as a button), and an 'input'.   This 'input' key can be specified as the 'podstate' parameter.  This field is not URL encoded and will be required to URL
@@ -352,11 +728,19 @@ type Pod struct {
 	Scanner string `json:"scanner"`
 
 	//Marks the pod that displays the closest thing to a simple "answer" that Wolfram|Alpha can provide
-	// Primary bool `json:"primary,omitempty"`
+	Primary bool `json:"primary,omitempty"`
+
+	// The URL to fetch the content of the pod from when the query was made with async=true and the pod was not ready in
+	//	time (see FetchAsyncPod).  Empty if the pod is complete.
+	Async string `json:"async,omitempty"`
 
 	// true or false depending on whether a serious processing error occurred with this specific pod. If true, there will be an <error> subelement
 	Error bool `json:"error"`
 
+	// The code and message of the error with this pod, nil if no error or none was given.  In json the error subelement
+	//	replaces the boolean, see Pod.UnmarshalJSON.
+	ErrorDetail *Error `json:"-"`
+
 	// A number indicating the intended position of the pod in a visual display. These numbers are typically multiples of 100, and they form an increasing sequence from top to bottom.
 	Position int `json:"position"`
 
@@ -369,7 +753,57 @@ type Pod struct {
 	// List of definitions useful for interpreting the result
 	Definitions DefinitionList `json:"definitions"`
 
-	// Sounds     Sounds `json:"sounds"`
+	// Sounds related to the query, e.g. the note played for a query about a musical note
+	Sounds Sounds `json:"sounds"`
+
+	// The sources of the data of the pod, when given for the pod rather than the whole result (see SourceLinks)
+	Sources Sources `json:"sources"`
+}
+
+// UnmarshalJSON for a pod.   The error of a pod is false if no error, otherwise either true or an object with the code
+//	and message of the error, which is kept as the ErrorDetail.
+func (pod *Pod) UnmarshalJSON(data []byte) error {
+	type podFields Pod
+	aux := struct {
+		*podFields
+		Error json.RawMessage `json:"error"`
+	}{podFields: (*podFields)(pod)}
+	if err := jsonLib.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	pod.Error = false
+	pod.ErrorDetail = nil
+	errorData := bytes.TrimSpace(aux.Error)
+	switch {
+	case len(errorData) == 0, string(errorData) == "false", string(errorData) == "null":
+	case string(errorData) == "true":
+		pod.Error = true
+	case errorData[0] == '{':
+		var detail Error
+		if err := json.Unmarshal(errorData, &detail); err != nil {
+			return errors.WithMessage(err, "unable to interpret pod error")
+		}
+		pod.Error = true
+		pod.ErrorDetail = &detail
+	default:
+		return errors.Errorf("pod error json does not indicate a boolean or object (%s)", string(errorData))
+	}
+	return nil
+}
+
+// MarshalJSON for a pod, writing the error as Wolfram Alpha does so that the ErrorDetail is kept when the pod is
+//	encoded and decoded again.
+func (pod Pod) MarshalJSON() ([]byte, error) {
+	type podFields Pod
+	var podError interface{} = pod.Error
+	if pod.ErrorDetail != nil {
+		podError = pod.ErrorDetail
+	}
+	return json.Marshal(struct {
+		podFields
+		Error interface{} `json:"error"`
+	}{podFields: podFields(pod), Error: podError})
 }
 
 //If there was a sound related to the query, if you for example query a musical note
@@ -384,10 +818,132 @@ type Sound struct {
 	Type string `json:"type"`
 }
 
+// UnmarshalJSON for sounds.   As with the xml, the sounds may be an object wrapping the 'sound' entries, which are
+//	themselves a single object when only 1, otherwise an array.  The wrapper may also be left out, leaving just the sound
+//	object or array.   All of these are accepted.  A wrapper without a 'sound' entry has no sounds, keeping its count.
+func (s *Sounds) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("no bytes in sounds to unmarshall")
+	}
+
+	var sounds []Sound
+	switch data[0] {
+	case '{':
+		wrapper := struct {
+			Count *int            `json:"count"`
+			Sound json.RawMessage `json:"sound"`
+		}{}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return errors.WithMessage(err, "error interpreting sounds")
+		}
+		if len(wrapper.Sound) == 0 && wrapper.Count != nil {
+			// a wrapper without entries, e.g. {"count": 0}, rather than a single sound
+			s.Sound = nil
+			s.Count = *wrapper.Count
+			return nil
+		}
+		if len(wrapper.Sound) > 0 {
+			data = wrapper.Sound
+		}
+		if err := unmarshalObjectOrArray(data, &sounds); err != nil {
+			return errors.WithMessage(err, "error interpreting sounds")
+		}
+
+	case '[':
+		if err := json.Unmarshal(data, &sounds); err != nil {
+			return errors.WithMessage(err, "error interpreting sounds")
+		}
+
+	case 'n':
+		// null, no sounds
+
+	default:
+		return errors.Errorf("sounds json does not indicate an object or array (%s)", string(data[0]))
+	}
+
+	s.Sound = sounds
+	s.Count = len(sounds)
+	return nil
+}
+
+// Infos is the list of info entries of a pod.  The api denotes a 'count' property, but it is missing in the actual
+//	response, which is an array of entries or, when there is only 1, the entry itself (e.g. an object with a 'units'
+//	property when looking up UK), so both are accepted.
+type Infos []Info
+
+func (i *Infos) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObjectOrArray(data, (*[]Info)(i)); err != nil {
+		return errors.WithMessage(err, "error interpreting infos")
+	}
+	return nil
+}
+
 type Info struct {
-	Text string `json:"text"`
-	Img  []Img  `json:"img"`
-	Link []Link `json:"link"`
+	Text  string     `json:"text"`
+	Img   []Img      `json:"img"`
+	Link  []Link     `json:"link"`
+	Units []InfoUnit `json:"units"`
+}
+
+// InfoUnit explains a unit abbreviation used in the pod, e.g. "kg" is "kilograms".
+type InfoUnit struct {
+	Short string `json:"short"`
+	Long  string `json:"long"`
+}
+
+// UnmarshalJSON for an info entry.   Each of the images, links and units is an object when there is only 1, otherwise an
+//	array.   Links are returned as 'links' in json although 'link' is documented, so either is accepted.
+func (i *Info) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Text  string          `json:"text"`
+		Img   json.RawMessage `json:"img"`
+		Link  json.RawMessage `json:"link"`
+		Links json.RawMessage `json:"links"`
+		Units json.RawMessage `json:"units"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	info := Info{Text: aux.Text}
+	if err := unmarshalObjectOrArray(aux.Img, &info.Img); err != nil {
+		return errors.WithMessage(err, "error interpreting info img")
+	}
+	links := aux.Link
+	if len(links) == 0 {
+		links = aux.Links
+	}
+	if err := unmarshalObjectOrArray(links, &info.Link); err != nil {
+		return errors.WithMessage(err, "error interpreting info links")
+	}
+	if err := unmarshalObjectOrArray(aux.Units, &info.Units); err != nil {
+		return errors.WithMessage(err, "error interpreting info units")
+	}
+	*i = info
+	return nil
+}
+
+// unmarshalObjectOrArray unmarshals json that is either an array or a single object into the slice pointed to by v, in
+//	which case the slice holds just that object.   Empty or null json leaves the slice as nil.
+func unmarshalObjectOrArray(data []byte, v interface{}) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	switch data[0] {
+	case '{':
+		wrapped := make([]byte, 0, len(data)+2)
+		wrapped = append(wrapped, '[')
+		wrapped = append(wrapped, data...)
+		wrapped = append(wrapped, ']')
+		return json.Unmarshal(wrapped, v)
+	case '[':
+		return json.Unmarshal(data, v)
+	default:
+		return errors.Errorf("json does not indicate an object or array (%s)", string(data[0]))
+	}
 }
 
 type Link struct {
@@ -412,10 +968,9 @@ type Definition struct {
 //	otherwise a list.   A bespoke unmarshall is therefore required.   This is truelly aweful, and must be a better way
 //	of implementing this (todo)
 func (d *DefinitionList) UnmarshalJSON(data []byte) error {
-
-	fmt.Printf("\n\n** DEFINITION: %s\n\n", string(data))
-
-	if len(data) == 4 && string(data) == "null" {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		*d = nil
 		return nil
 	}
 
@@ -437,7 +992,6 @@ func (d *DefinitionList) UnmarshalJSON(data []byte) error {
 		}
 	}
 	return errors.New("definitions must be object or list")
-	return nil
 }
 
 //Each Source contains a link to a web page with the source information
@@ -451,12 +1005,61 @@ type Source struct {
 	Text string `json:"text"`
 }
 
+// UnmarshalJSON for sources.   As with assumptions, a single source is returned as an object rather than an array
+//	holding the object, so both are accepted.  The sources may also be wrapped as in the XML, {"count":2,"source":[...]};
+//	a wrapper without a 'source' entry has no sources, keeping its count.
+func (s *Sources) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("no bytes in sources to unmarshall")
+	}
+
+	switch data[0] {
+	case '{':
+		wrapper := struct {
+			Count  *int            `json:"count"`
+			Source json.RawMessage `json:"source"`
+		}{}
+		if err := json.Unmarshal(data, &wrapper); err == nil && len(wrapper.Source) > 0 {
+			return s.UnmarshalJSON(wrapper.Source)
+		} else if err == nil && wrapper.Count != nil {
+			// a wrapper without entries, e.g. {"count":0}, rather than a single source
+			s.Source = nil
+			s.Count = *wrapper.Count
+			return nil
+		}
+
+		// unmarshal single source
+		s.Count = 1
+		s.Source = make([]Source, 1)
+		if err := json.Unmarshal(data, &s.Source[0]); err != nil {
+			return errors.WithMessage(err, "error interpreting source")
+		}
+
+	case '[':
+		if err := jsonLib.Unmarshal(data, &s.Source); err != nil {
+			return errors.WithMessage(err, "error interpreting sources")
+		}
+		s.Count = len(s.Source)
+
+	case 'n':
+		// null, no sources
+		s.Count = 0
+		s.Source = nil
+
+	default:
+		return errors.Errorf("sources json does not indicate an object or array (%s)", string(data[0]))
+	}
+	return nil
+}
+
 // State denotes a refinement of pod detail.  A query will result in pods that have have more detail (states) that can be
 //	refined.  The 'name' is the button on wolfram alpha.  The 'input' is a non-url encoded value that can be specified as
 //	a podstate prop in additional request (so will need url encoding).
 type State struct {
-	Name  string `json:"name"`
-	Input string `json:"input"` // n.b the 'podstate' prop and non URL endoded to refine detail for a pod.
+	Name       string `json:"name"`
+	Input      string `json:"input"`      // n.b the 'podstate' prop and non URL endoded to refine detail for a pod.
+	StepByStep bool   `json:"stepbystep"` // true if the state gives a step-by-step solution
 }
 
 type SubPod struct {
@@ -466,6 +1069,15 @@ type SubPod struct {
 	//Textual representation of the subpod
 	Plaintext string `json:"plaintext"`
 
+	//MathML markup of the subpod for rendering equations, only present if the mathml format is requested
+	MathML string `json:"mathml"`
+
+	//Wolfram Language input giving the subpod, only present if the minput format is requested
+	Minput string `json:"minput"`
+
+	//Wolfram Language expression of the subpod, only present if the moutput format is requested
+	Moutput string `json:"moutput"`
+
 	//Usually an empty string because most subpod elements don't have a title
 	Title string `json:"title"`
 }
@@ -497,67 +1109,548 @@ type Img struct {
 // Additional information about parameters can be found at
 // http://products.wolframalpha.com/docs/WolframAlpha-API-Reference.pdf, page 42
 func (c *Client) GetQueryResult(query string, params url.Values) (*QueryResult, error) {
-	query = url.QueryEscape(query)
+	return c.getQueryResult(context.Background(), query, params)
+}
 
-	url := fmt.Sprintf("https://api.wolframalpha.com/v2/query?input=%s&appid=%s&output=JSON", query, c.AppID)
-	if params != nil {
-		url += "&" + params.Encode()
+// GetQueryResultWithUnits gets the query result with measurements in the given unit system, as with the units argument
+//	of GetShortAnswerQuery and GetSpokenAnswerQuery.  params must not also hold "units".   An unknown unit system is an
+//	error, as with WithUnits.
+func (c *Client) GetQueryResultWithUnits(query string, units Unit, params url.Values) (*QueryResult, error) {
+	if err := checkReservedParams(params, "units"); err != nil {
+		return nil, err
 	}
 
-	res, err := http.Get(url)
-	if err != nil {
-		return nil, errors.WithMessage(err, "error in wolfram alpha http request")
+	value := units.param()
+	if value == "" {
+		return nil, errors.Errorf("unknown unit system %s", units)
+	}
+	unitParams := cloneParams(params)
+	unitParams.Set("units", value)
+	return c.getQueryResult(context.Background(), query, unitParams)
+}
+
+// GetQueryResultWithPodStates gets the query result with the given pod states applied, e.g. to request more detail of a
+//	pod as the buttons on wolfram alpha do.  Each pod state is the Input of a State as it was given in the response, e.g.
+//	"Population:CityData__More", and must not be encoded as it is encoded by this method (see EncodePodState).
+func (c *Client) GetQueryResultWithPodStates(query string, podStates []string, params url.Values) (*QueryResult, error) {
+	encoded := make([]string, 0, len(podStates))
+	for _, podState := range podStates {
+		if podState == "" {
+			return nil, errors.New("empty pod state given")
+		}
+		encoded = append(encoded, "podstate="+EncodePodState(podState))
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
+	result, err := c.getQueryResult(context.Background(), query, params, encoded...)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error in obtaining full wolfram alpha http result")
+		return nil, err
 	}
+	result.PodStates = append([]string(nil), podStates...)
+	return result, nil
+}
 
-	// todo: remove json dump of result
-	jsonResult, _ := PrettyJsonFromRaw((*json.RawMessage)(&body))
-	fmt.Printf("*********\nGetQueryResult JSON\n%s\n", jsonResult)
+// ApplyPodStates gets the result again with the selected states of its pods applied on top of those it was made with
+//	(its PodStates), e.g. as each button on wolfram alpha is pressed in turn.  Wolfram Alpha needs every state of a
+//	chain to be given together, as the states offered by a pod depend on those already applied to it: pressing "More"
+//	on a pod already expanded by "More" gives the same input again, which expands it further only when sent with the
+//	first.  So prior states are kept in order, including repeats, and the new ones follow in the order selected.  params
+//	are the other parameters of the query, which are not recorded in the result.
+func (c *Client) ApplyPodStates(result *QueryResult, selected []State, params url.Values) (*QueryResult, error) {
+	if len(selected) == 0 {
+		return nil, errors.New("no pod states selected")
+	}
 
-	data := &Query{}
-	data.Result.Query = query
+	podStates := append([]string(nil), result.PodStates...)
+	for _, state := range selected {
+		podStates = append(podStates, state.Input)
+	}
+	return c.GetQueryResultWithPodStates(result.Query, podStates, params)
+}
 
-	if err = jsonLib.Unmarshal(body, &data); err != nil {
-		return nil, errors.WithMessage(err, "unable to interpret wolfram alpha json result")
+// StepByStepState returns the state of the pod giving a step-by-step solution, e.g. "Result__Step-by-step solution",
+//	to be applied with ApplyPodStates.  ok is false if the pod has no step-by-step solution.
+func (pod *Pod) StepByStepState() (State, bool) {
+	for _, state := range pod.States {
+		if state.StepByStep || strings.Contains(strings.ToLower(state.Name), "step-by-step") {
+			return state, true
+		}
 	}
+	return State{}, false
+}
 
-	return &data.Result, err
+// GetQueryResultRaw gets the query result as GetQueryResult does, along with the json body it was decoded from, e.g.
+//	for debugging or to extract fields that QueryResult does not model.  The body is returned whenever one was
+//	received, including when it could not be decoded.
+func (c *Client) GetQueryResultRaw(query string, params url.Values) (*QueryResult, json.RawMessage, error) {
+	return c.getQueryResultRaw(context.Background(), query, params)
 }
 
-// Gets the json from the API and assigns the data to the target.
-// The target being a QueryResult struct
-func unmarshal(body *http.Response, target interface{}) error {
-	defer body.Body.Close()
-	return json.NewDecoder(body.Body).Decode(target)
+// getQueryResult is GetQueryResult with the request bound to ctx.  encoded are parameters already encoded for a query
+//	string, e.g. "assumption=*C.pi-_*NamedConstant-", added after params.
+func (c *Client) getQueryResult(ctx context.Context, query string, params url.Values, encoded ...string) (*QueryResult, error) {
+	result, _, err := c.queryResult(ctx, query, params, false, encoded...)
+	return result, err
 }
 
-// GetSimpleQuery gets an image from the `simple` endpoint.
-//
-// Returns the image as a response body, the query url, and an error
-//
-// Can take some extra parameters, e.g `background=F5F5F5`
-// sets the background color to #F5F5F5
-//
-// The rest of the parameters can be found here https://products.wolframalpha.com/simple-api/documentation/
-func (c *Client) GetSimpleQuery(query string, params url.Values) (io.ReadCloser, string, error) {
-	query = url.QueryEscape(query)
+// getQueryResultRaw is getQueryResult also returning the body of the response.
+func (c *Client) getQueryResultRaw(ctx context.Context, query string, params url.Values, encoded ...string) (*QueryResult, json.RawMessage, error) {
+	return c.queryResult(ctx, query, params, true, encoded...)
+}
 
-	query = fmt.Sprintf("http://api.wolframalpha.com/v1/simple?appid=%s&input=%s&output=json", c.AppID, query)
-	if params != nil {
-		query += "&" + params.Encode()
+// queryResult makes a request of the full results API, returning the body of the response if raw is true.
+func (c *Client) queryResult(ctx context.Context, query string, params url.Values, raw bool, encoded ...string) (*QueryResult, json.RawMessage, error) {
+	end, err := c.begin()
+	if err != nil {
+		return nil, nil, err
 	}
+	defer end()
 
-	res, err := http.Get(query)
+	if err := c.checkAppID(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkReservedParams(params, "input", "appid", "output"); err != nil {
+		return nil, nil, err
+	}
+
+	return c.fetchQueryResult(ctx, "query", c.queryURL(ctx, url.QueryEscape(query), OutputJSON, params, encoded...), query, raw)
+}
+
+// GetQueryResultXML gets the query result as XML (output=XML), for use with existing XML tooling.  The result is
+//	returned as it was received without being interpreted.
+func (c *Client) GetQueryResultXML(query string, params url.Values) (string, error) {
+	end, err := c.begin()
+	if err != nil {
+		return "", err
+	}
+	defer end()
+
+	if err := c.checkAppID(context.Background()); err != nil {
+		return "", err
+	}
+
+	if err := checkReservedParams(params, "input", "appid", "output"); err != nil {
+		return "", err
+	}
 
+	res, err := c.get(context.Background(), "query", c.queryURL(context.Background(), url.QueryEscape(query), OutputXML, params))
+	if err != nil {
+		return "", errors.WithMessage(err, "error in wolfram alpha http request")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", errors.WithMessage(err, "error in obtaining full wolfram alpha http result")
+	}
+	return string(body), nil
+}
+
+// Output is the format of a result of the full results API, requested with the output parameter.   Results are
+//	decoded from JSON; XML is only returned as it was received (see GetQueryResultXML).
+type Output string
+
+const (
+	OutputJSON Output = "JSON"
+	OutputXML  Output = "XML"
+)
+
+// queryURL returns the url of the full results API for the escaped query in the given output format, with the AppID of
+//	ctx.  encoded are parameters already encoded for a query string, added after params.
+func (c *Client) queryURL(ctx context.Context, escapedQuery string, output Output, params url.Values, encoded ...string) string {
+	queryURL := fmt.Sprintf("%s/v2/query?input=%s&appid=%s&output=%s", c.baseURL(), escapedQuery, c.appID(ctx), output)
+	if params != nil {
+		queryURL += "&" + params.Encode()
+	}
+	for _, param := range encoded {
+		queryURL += "&" + param
+	}
+	return queryURL
+}
+
+// fetchQueryResult requests a full result from the url of the endpoint and decodes it, returning the result with the
+//	body of the response if raw is true.  query is recorded as the Query of the result.   The body is only held in
+//	memory when it is wanted, by raw, the Logger or OnDecodeError; otherwise the result is decoded as it is read.
+func (c *Client) fetchQueryResult(ctx context.Context, endpoint string, url string, query string, raw bool) (*QueryResult, json.RawMessage, error) {
+	res, err := c.get(ctx, endpoint, url)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error in wolfram alpha http request")
+	}
+	defer res.Body.Close()
+
+	var result *QueryResult
+	var body []byte
+	if raw || c.Logger != nil || c.OnDecodeError != nil {
+		body, err = io.ReadAll(res.Body)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "error in obtaining full wolfram alpha http result")
+		}
+
+		if c.Logger != nil {
+			jsonResult, _ := PrettyJsonFromRaw((*json.RawMessage)(&body))
+			c.Logger.Printf("GetQueryResult JSON\n%s", jsonResult)
+		}
+
+		data := &Query{}
+		data.Result.Query = query
+
+		if err = c.decodeQueryResult(body, data); err != nil {
+			return nil, body, errors.WithMessage(err, "unable to interpret wolfram alpha json result")
+		}
+		result = &data.Result
+	} else {
+		result = &QueryResult{Query: query}
+		if err = decodeQueryResultStream(res.Body, result); err != nil {
+			return nil, nil, errors.WithMessage(err, "unable to interpret wolfram alpha json result")
+		}
+	}
+
+	if c.SortPodsOnDecode {
+		result.SortPods()
+	}
+
+	// capability errors are returned rather than left in the result so that they are not mistaken for transient failures
+	if errors.Is(result.Error.Err, ErrPlanRequired) {
+		return nil, body, result.Error.Err
+	}
+
+	return result, body, nil
+}
+
+// decodeQueryResultStream decodes a full result from the body as it is read, without holding the whole body in memory.
+//	The result is decoded through its fields rather than UnmarshalJSON, which would need the whole of it at once.
+func decodeQueryResultStream(body io.Reader, result *QueryResult) error {
+	data := struct {
+		Result *resultFields `json:"queryresult"`
+	}{(*resultFields)(result)}
+	if err := jsonLib.NewDecoder(body).Decode(&data); err != nil {
+		return err
+	}
+	result.ErrorDetail = result.Error.detail()
+	return nil
+}
+
+// decodeQueryResult unmarshalls a full result body into data.  If the client has an OnDecodeError callback then a
+//	field that fails to decode is reported and skipped, and each pod is decoded alone so that one bad pod does not
+//	lose the others.
+func (c *Client) decodeQueryResult(body []byte, data *Query) error {
+	err := jsonLib.Unmarshal(body, data)
+	if err == nil || c.OnDecodeError == nil {
+		return err
+	}
+
+	raw := struct {
+		Result map[string]json.RawMessage `json:"queryresult"`
+	}{}
+	if err := jsonLib.Unmarshal(body, &raw); err != nil {
+		// the document itself is malformed, nothing can be salvaged
+		return err
+	}
+
+	// start again from a clean result, as the failed attempt may have partially populated fields
+	result := QueryResult{Query: data.Result.Query}
+
+	fields := make([]string, 0, len(raw.Result))
+	for field := range raw.Result {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		value := raw.Result[field]
+
+		if strings.EqualFold(field, "pods") {
+			var pods []json.RawMessage
+			if err := jsonLib.Unmarshal(value, &pods); err != nil {
+				c.OnDecodeError(field, err)
+				continue
+			}
+			for i, rawPod := range pods {
+				var pod Pod
+				if err := jsonLib.Unmarshal(rawPod, &pod); err != nil {
+					c.OnDecodeError(fmt.Sprintf("%s[%d]", field, i), err)
+					continue
+				}
+				result.Pods = append(result.Pods, pod)
+			}
+			continue
+		}
+
+		// decode the field alone into a scratch result first so that a failure cannot leave it half populated
+		fragment, err := jsonLib.Marshal(map[string]json.RawMessage{field: value})
+		if err != nil {
+			c.OnDecodeError(field, err)
+			continue
+		}
+		var scratch QueryResult
+		if err := jsonLib.Unmarshal(fragment, &scratch); err != nil {
+			c.OnDecodeError(field, err)
+			continue
+		}
+		if err := jsonLib.Unmarshal(fragment, &result); err != nil {
+			c.OnDecodeError(field, err)
+		}
+	}
+
+	data.Result = result
+	return nil
+}
+
+// cloneParams returns a copy of params that can be changed without changing those of the caller, empty if params is nil.
+func cloneParams(params url.Values) url.Values {
+	clone := url.Values{}
+	for key, values := range params {
+		clone[key] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// checkReservedParams returns ErrReservedParam if params holds any of the reserved parameters, which are set by the
+//	client itself.  Wolfram Alpha parameter names are not case sensitive so neither is the check.
+func checkReservedParams(params url.Values, reserved ...string) error {
+	for key := range params {
+		for _, name := range reserved {
+			if strings.EqualFold(key, name) {
+				return errors.WithMessagef(ErrReservedParam, "'%s' cannot be passed as a parameter", key)
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	// defaultBaseURL is the base URL of the api.wolframalpha.com host
+	defaultBaseURL = "https://api.wolframalpha.com"
+
+	// defaultWebBaseURL is the base URL of the www.wolframalpha.com host
+	defaultWebBaseURL = "https://www.wolframalpha.com"
+)
+
+// baseURL returns the base URL of the api.wolframalpha.com host, without a trailing slash
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return defaultBaseURL
+	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+// webBaseURL returns the base URL of the www.wolframalpha.com host, without a trailing slash
+func (c *Client) webBaseURL() string {
+	if c.WebBaseURL == "" {
+		return defaultWebBaseURL
+	}
+	return strings.TrimRight(c.WebBaseURL, "/")
+}
+
+// get performs a GET request of the url of the endpoint, bound to ctx and limited to the RequestTimeout of the client.
+//	A response with a status other than 2xx is returned as an *APIError, with the body read and closed.
+func (c *Client) get(ctx context.Context, endpoint string, url string) (*http.Response, error) {
+	if c.RequestTimeout <= 0 {
+		return c.do(ctx, endpoint, url)
+	}
+
+	// the timeout runs until the body is closed, as it is read after returning
+	ctx, cancel := context.WithTimeout(ctx, c.RequestTimeout)
+	res, err := c.do(ctx, endpoint, url)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{body: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// do performs the request of get, bound to ctx.
+func (c *Client) do(ctx context.Context, endpoint string, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent())
+
+	// the header is set here rather than left to http.Transport, which would then decompress the response itself, so
+	//	that a custom transport or one with compression disabled still gets compressed responses
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return nil, errors.WithMessage(err, "error waiting on rate limiter")
+		}
+	}
+
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := decompressBody(res); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+		return nil, &APIError{
+			Endpoint:   endpoint,
+			StatusCode: res.StatusCode,
+			Body:       strings.TrimSpace(string(body)),
+			URL:        redactAppID(url),
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	return res, nil
+}
+
+// appIDKey is the key of the AppID of a context given by ContextWithAppID.
+type appIDKey struct{}
+
+// ContextWithAppID returns a copy of ctx with which requests are made with the given AppID in place of that of the
+//	client, e.g. to spread queries across the quota of several AppIDs with one Client.  The AppID applies to the
+//	methods taking a context; the others use the AppID of the client.
+func ContextWithAppID(ctx context.Context, appID string) context.Context {
+	return context.WithValue(ctx, appIDKey{}, appID)
+}
+
+// appID returns the AppID to make requests bound to ctx with, that given by ContextWithAppID if any, otherwise the
+//	AppID of the client.
+func (c *Client) appID(ctx context.Context) string {
+	if appID, ok := ctx.Value(appIDKey{}).(string); ok {
+		return appID
+	}
+	return c.AppID
+}
+
+// checkAppID returns ErrMissingAppID if there is no AppID for requests bound to ctx, or an error matching
+//	ErrInvalidAppID if it cannot be valid, so that a request is not made only to be refused.
+func (c *Client) checkAppID(ctx context.Context) error {
+	appID := c.appID(ctx)
+	if strings.TrimSpace(appID) == "" {
+		return ErrMissingAppID
+	}
+	if strings.IndexFunc(appID, unicode.IsSpace) >= 0 {
+		return errors.WithMessage(ErrInvalidAppID, "appid contains whitespace")
+	}
+	return nil
+}
+
+// decompressBody replaces the body of a gzip encoded response with one that decompresses it as it is read.
+func decompressBody(res *http.Response) error {
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(res.Body)
+	if err == io.EOF {
+		// nothing was sent, so there is nothing to decompress
+		reader = nil
+	} else if err != nil {
+		return errors.WithMessage(err, "error decompressing wolfram alpha response")
+	}
+
+	res.Body = &gzipBody{reader: reader, body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+	return nil
+}
+
+// gzipBody is the body of a response decompressed by reader, nil if the body was empty.
+type gzipBody struct {
+	reader *gzip.Reader
+	body   io.ReadCloser
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		return 0, io.EOF
+	}
+	return b.reader.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	if b.reader != nil {
+		b.reader.Close()
+	}
+	return b.body.Close()
+}
+
+// cancelBody is the body of a response whose context is cancelled once it is closed.
+type cancelBody struct {
+	body   io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Read(p []byte) (int, error) {
+	return b.body.Read(p)
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.body.Close()
+}
+
+// userAgent returns the User-Agent header to send with each request
+func (c *Client) userAgent() string {
+	if c.UserAgent == "" {
+		return DefaultUserAgent
+	}
+	return c.UserAgent
+}
+
+// httpClient returns the client to make requests with
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// GetSimpleQuery gets an image from the `simple` endpoint.
+//
+// Returns the image as a response body, the query url, and an error
+//
+// Can take some extra parameters, e.g `background=F5F5F5`
+// sets the background color to #F5F5F5
+//
+// The rest of the parameters can be found here https://products.wolframalpha.com/simple-api/documentation/
+func (c *Client) GetSimpleQuery(query string, params url.Values) (io.ReadCloser, string, error) {
+	res, query, end, err := c.simpleQuery(context.Background(), query, params)
 	if err != nil {
 		return nil, "", err
 	}
 
-	return res.Body, query, err
+	// the request remains in flight until the caller has finished with the body
+	return &trackedBody{ReadCloser: res.Body, end: end}, query, nil
+}
+
+// simpleQuery makes a request of the simple endpoint, returning the response with the query url and the function to call
+//	once finished with the response, which ends the operation in flight.
+func (c *Client) simpleQuery(ctx context.Context, query string, params url.Values) (*http.Response, string, func(), error) {
+	end, err := c.begin()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if err := c.checkAppID(ctx); err != nil {
+		end()
+		return nil, "", nil, err
+	}
+
+	query = url.QueryEscape(query)
+
+	query = fmt.Sprintf("%s/v1/simple?appid=%s&input=%s&output=json", c.baseURL(), c.appID(ctx), query)
+	if params != nil {
+		query += "&" + params.Encode()
+	}
+
+	res, err := c.get(ctx, "simple", query)
+	if err != nil {
+		end()
+		return nil, "", nil, err
+	}
+	return res, query, end, nil
 }
 
 type Unit int
@@ -567,54 +1660,189 @@ const (
 	Metric
 )
 
-func (c *Client) GetShortAnswerQuery(query string, units Unit, timeout int) (string, error) {
-	query = url.QueryEscape(query)
-
-	switch units {
+// param returns the value of the units parameter for the unit system, empty if unknown
+func (u Unit) param() string {
+	switch u {
 	case Imperial:
-		query += "&units=imperial"
+		return "imperial"
 	case Metric:
-		query += "&units=metric"
+		return "metric"
+	}
+	return ""
+}
+
+// String returns the name of the unit system as used for the units parameter, "imperial" or "metric".
+func (u Unit) String() string {
+	if value := u.param(); value != "" {
+		return value
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// ParseUnit returns the unit system named by s, "imperial" or "metric" in any case, e.g. from configuration.
+func ParseUnit(s string) (Unit, error) {
+	for _, u := range []Unit{Imperial, Metric} {
+		if strings.EqualFold(strings.TrimSpace(s), u.param()) {
+			return u, nil
+		}
 	}
+	return 0, errors.Errorf("unknown unit system '%s'", s)
+}
+
+// answerURL returns the url for the query on an answer endpoint of the v1 API, "result" for the short answer or
+//	"spoken" for the spoken answer.  These endpoints return plaintext, so there is no output parameter.
+func (c *Client) answerURL(ctx context.Context, endpoint string, query string, units Unit, timeout int) string {
+	// the input is escaped on its own (with spaces as %20) so that the units and timeout are always parameters of their
+	//	own rather than part of the input
+	answerURL := fmt.Sprintf("%s/v1/%s?appid=%s&i=%s", c.baseURL(), endpoint, c.appID(ctx),
+		strings.ReplaceAll(url.QueryEscape(query), "+", "%20"))
 
+	if value := units.param(); value != "" {
+		answerURL += "&units=" + value
+	}
 	if timeout != 0 {
-		query += "&timeout=" + strconv.Itoa(timeout)
+		answerURL += "&timeout=" + strconv.Itoa(timeout)
+	}
+	return answerURL
+}
+
+// checkAnswerTimeout returns an error if the timeout in seconds of an answer endpoint is negative.  Zero is not sent, so
+//	that Wolfram Alpha's default of 5 seconds applies.
+func checkAnswerTimeout(timeout int) error {
+	if timeout < 0 {
+		return errors.Errorf("timeout must not be negative, got %d", timeout)
+	}
+	return nil
+}
+
+// GetShortAnswerQuery gets the short answer to the query as plaintext.   If Wolfram Alpha has no short answer the error
+//	matches ErrNoShortAnswer, and ErrInvalidAppID if the AppID was refused (see APIError).   timeout is the time in
+//	seconds Wolfram Alpha may take, zero for its default of 5 seconds; a negative timeout is an error.
+func (c *Client) GetShortAnswerQuery(query string, units Unit, timeout int) (string, error) {
+	end, err := c.begin()
+	if err != nil {
+		return "", err
+	}
+	defer end()
+
+	if err := c.checkAppID(context.Background()); err != nil {
+		return "", err
+	}
+	if err := checkAnswerTimeout(timeout); err != nil {
+		return "", err
 	}
-	query = fmt.Sprintf("https://api.wolframalpha.com/v1/result?appid=%s&i=%s&output=json", c.AppID, query)
-	res, err := http.Get(query)
+
+	res, err := c.get(context.Background(), "result", c.answerURL(context.Background(), "result", query, units, timeout))
 	if err != nil {
 		return "", err
 	}
 
 	defer res.Body.Close()
-	b, err := ioutil.ReadAll(res.Body)
+	b, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", err
 	}
 	return string(b), nil
 }
 
+// GetShortAnswerQueryTimeout gets the short answer to the query as GetShortAnswerQuery does, with the timeout as a
+//	duration.  Wolfram Alpha takes whole seconds, so the timeout is rounded up to the next second.
+func (c *Client) GetShortAnswerQueryTimeout(query string, units Unit, timeout time.Duration) (string, error) {
+	if timeout < 0 {
+		return "", errors.Errorf("timeout must not be negative, got %s", timeout)
+	}
+	return c.GetShortAnswerQuery(query, units, int((timeout+time.Second-1)/time.Second))
+}
+
+// GetSpokenAnswerQuery gets the answer to the query as a sentence suited to text to speech, with the same errors as
+//	GetShortAnswerQuery.  See GetSpokenResult for the url requested too.
 func (c *Client) GetSpokenAnswerQuery(query string, units Unit, timeout int) (string, error) {
-	query = url.QueryEscape(query)
+	result, err := c.spokenResult(context.Background(), query, units, timeout)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
 
-	switch units {
-	case Imperial:
-		query += "&units=imperial"
-	case Metric:
-		query += "&units=metric"
+// SpokenResult is the answer of the spoken endpoint.
+type SpokenResult struct {
+	Text string // the answer as a sentence, with surrounding whitespace trimmed
+	URL  string // the url requested, with the AppID redacted
+}
+
+// GetSpokenResult gets the answer to the query as a sentence suited to text to speech, with the url requested for
+//	debugging.  The errors are those of GetShortAnswerQuery, and an empty answer is reported as ErrNoShortAnswer.
+func (c *Client) GetSpokenResult(query string, units Unit, timeout int) (*SpokenResult, error) {
+	return c.spokenResult(context.Background(), query, units, timeout)
+}
+
+// spokenResult is GetSpokenResult with the request bound to ctx
+func (c *Client) spokenResult(ctx context.Context, query string, units Unit, timeout int) (*SpokenResult, error) {
+	end, err := c.begin()
+	if err != nil {
+		return nil, err
 	}
+	defer end()
 
-	if timeout != 0 {
-		query += "&timeout=" + strconv.Itoa(timeout)
+	if err := c.checkAppID(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkAnswerTimeout(timeout); err != nil {
+		return nil, err
+	}
+
+	spokenURL := c.answerURL(ctx, "spoken", query, units, timeout)
+	res, err := c.get(ctx, "spoken", spokenURL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(string(b))
+	if text == "" {
+		return nil, errors.WithMessage(ErrNoShortAnswer, "empty spoken answer")
+	}
+	return &SpokenResult{Text: text, URL: redactAppID(spokenURL)}, nil
+}
+
+// GetLLMQuery gets the result of the query from the LLM API, which returns plaintext intended to be passed to a large
+//	language model.
+//
+// Can take extra parameters, e.g. `maxchars=500` limits the length of the response
+//
+// The parameters can be found here https://products.wolframalpha.com/llm-api/documentation
+func (c *Client) GetLLMQuery(query string, params url.Values) (string, error) {
+	end, err := c.begin()
+	if err != nil {
+		return "", err
+	}
+	defer end()
+
+	if err := c.checkAppID(context.Background()); err != nil {
+		return "", err
 	}
-	query = fmt.Sprintf("https://api.wolframalpha.com/v1/spoken?appid=%s&i=%s&output=json", c.AppID, query)
-	res, err := http.Get(query)
+
+	if err := checkReservedParams(params, "input", "appid"); err != nil {
+		return "", err
+	}
+
+	query = fmt.Sprintf("%s/api/v1/llm-api?appid=%s&input=%s", c.webBaseURL(), c.AppID, url.QueryEscape(query))
+	if params != nil {
+		query += "&" + params.Encode()
+	}
+
+	res, err := c.get(context.Background(), "llm-api", query)
 	if err != nil {
 		return "", err
 	}
 
 	defer res.Body.Close()
-	b, err := ioutil.ReadAll(res.Body)
+	b, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", err
 	}
@@ -628,47 +1856,190 @@ const (
 	Voice
 )
 
-type FastQueryResult struct {
-	Version            string `json:"version"`
-	SpellingCorrection string `json:"spellingCorretion"`
-	BuildNumber        string `json:"buildnumber"`
-	Query              []*struct {
-		I                       string `json:"i"`
-		Accepted                string `json:"accepted"`
-		Timing                  string `json:"timing"`
-		Domain                  string `json:"domain"`
-		ResultSignificanceScore string `json:"resultsignificancescore"`
-		SummaryBox              *struct {
-			Path string `json:"path"`
-		} `json:"summarybox"`
-	} `json:"query"`
-}
-
-func (c *Client) GetFastQueryRecognizer(query string, mode Mode) (*FastQueryResult, error) {
-	query = url.QueryEscape(query)
-
-	switch mode {
+// param returns the value of the mode parameter for the mode, empty if unknown
+func (m Mode) param() string {
+	switch m {
 	case Default:
-		query += "&mode=Default"
+		return "Default"
 	case Voice:
-		query += "&mode=Voice"
+		return "Voice"
+	}
+	return ""
+}
+
+// String returns the name of the mode as used for the mode parameter, "Default" or "Voice".
+func (m Mode) String() string {
+	if value := m.param(); value != "" {
+		return value
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode returns the mode named by s, "default" or "voice" in any case.
+func ParseMode(s string) (Mode, error) {
+	for _, m := range []Mode{Default, Voice} {
+		if strings.EqualFold(strings.TrimSpace(s), m.param()) {
+			return m, nil
+		}
+	}
+	return 0, errors.Errorf("unknown mode '%s'", s)
+}
+
+type FastQueryResult struct {
+	Version            string       `json:"version"`
+	SpellingCorrection string       `json:"spellingCorrection"`
+	BuildNumber        string       `json:"buildnumber"`
+	Query              []*FastQuery `json:"query"`
+}
+
+// Build returns the build number of the recognizer as a number
+func (fqr *FastQueryResult) Build() (int, error) {
+	build, err := strconv.Atoi(strings.TrimSpace(fqr.BuildNumber))
+	if err != nil {
+		return 0, errors.WithMessage(err, "unable to interpret fast query recognizer build number")
+	}
+	return build, nil
+}
+
+// VersionNumber returns the major and minor parts of the recognizer version (e.g. 0 and 2 for "0.2")
+func (fqr *FastQueryResult) VersionNumber() (int, int, error) {
+	parts := strings.SplitN(strings.TrimSpace(fqr.Version), ".", 2)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, errors.WithMessage(err, "unable to interpret fast query recognizer version")
+	}
+	minor := 0
+	if len(parts) == 2 {
+		if minor, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, errors.WithMessage(err, "unable to interpret fast query recognizer version")
+		}
+	}
+	return major, minor, nil
+}
+
+// FastQuery is the recognition of a single query by the fast query recognizer
+type FastQuery struct {
+	I                       string      `json:"i"`
+	Accepted                string      `json:"accepted"`
+	Timing                  string      `json:"timing"`
+	Domain                  string      `json:"domain"`
+	ResultSignificanceScore string      `json:"resultsignificancescore"`
+	SummaryBox              *SummaryBox `json:"summarybox"` // nil if the query has no summary box
+}
+
+// TimingFloat returns the time the recognizer took for the query as a number, as the Timing of a QueryResult is
+func (fq *FastQuery) TimingFloat() (float64, error) {
+	timing, err := strconv.ParseFloat(strings.TrimSpace(fq.Timing), 64)
+	if err != nil {
+		return 0, errors.WithMessage(err, "unable to interpret fast query recognizer timing")
+	}
+	return timing, nil
+}
+
+// ResultSignificanceScoreFloat returns the result significance score of the query as a number
+func (fq *FastQuery) ResultSignificanceScoreFloat() (float64, error) {
+	score, err := strconv.ParseFloat(strings.TrimSpace(fq.ResultSignificanceScore), 64)
+	if err != nil {
+		return 0, errors.WithMessage(err, "unable to interpret fast query recognizer result significance score")
+	}
+	return score, nil
+}
+
+// SummaryBox refers to the summary of a recognized query
+type SummaryBox struct {
+	Path string `json:"path"`
+}
+
+// UnmarshalJSON for the summary box.  The recognizer normally returns an object but has been seen to return an array
+//	(empty, or holding the summary box), so the first element of an array is taken.
+func (sb *SummaryBox) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("no bytes in summary box to unmarshall")
 	}
 
-	query = fmt.Sprintf(
-		"https://www.wolframalpha.com/queryrecognizer/query.jsp?appid=%s&i=%s&output=json", c.AppID, query,
-	)
+	// an alias type is used so that the standard unmarshalling does not recurse back into this method
+	type summaryBox SummaryBox
 
-	res, err := http.Get(query)
+	switch data[0] {
+	case '{':
+		return json.Unmarshal(data, (*summaryBox)(sb))
+
+	case '[':
+		var boxes []summaryBox
+		if err := json.Unmarshal(data, &boxes); err != nil {
+			return errors.WithMessage(err, "error interpreting summary box")
+		}
+		if len(boxes) > 0 {
+			*sb = SummaryBox(boxes[0])
+		}
+
+	default:
+		return errors.Errorf("summary box json does not indicate an object or array (%s)", string(data[0]))
+	}
+	return nil
+}
+
+// GetFastQueryRecognizer asks the fast query recognizer whether the query is likely to be answered by Wolfram Alpha.
+//	params are optional further parameters of the recognizer, merged in the order given.   ErrReservedParam is
+//	returned if any of them sets appid, i, output or mode, which are set by this method.
+func (c *Client) GetFastQueryRecognizer(query string, mode Mode, params ...url.Values) (*FastQueryResult, error) {
+	body, err := c.GetFastQueryRecognizerRaw(query, mode, params...)
 	if err != nil {
 		return nil, err
 	}
 
 	qres := &FastQueryResult{}
-	err = unmarshal(res, qres)
+	if err = json.Unmarshal(body, qres); err != nil {
+		return nil, errors.WithMessage(err, "unable to interpret fast query recognizer json result")
+	}
+	return qres, nil
+}
+
+// GetFastQueryRecognizerRaw returns the unparsed JSON response of the fast query recognizer, for inspecting responses
+//	whose shape does not match FastQueryResult.
+func (c *Client) GetFastQueryRecognizerRaw(query string, mode Mode, params ...url.Values) (json.RawMessage, error) {
+	end, err := c.begin()
 	if err != nil {
 		return nil, err
 	}
-	return qres, nil
+	defer end()
+
+	ctx := context.Background()
+	if err := c.checkAppID(ctx); err != nil {
+		return nil, err
+	}
+	for _, extra := range params {
+		if err := checkReservedParams(extra, "appid", "i", "output", "mode"); err != nil {
+			return nil, err
+		}
+	}
+
+	query = url.QueryEscape(query)
+
+	if value := mode.param(); value != "" {
+		query += "&mode=" + value
+	}
+
+	// the recognizer supports only json and xml output, json being what FastQueryResult models
+	query = fmt.Sprintf("%s/queryrecognizer/query.jsp?appid=%s&i=%s&output=json", c.webBaseURL(), c.appID(ctx), query)
+	for _, extra := range params {
+		if len(extra) > 0 {
+			query += "&" + extra.Encode()
+		}
+	}
+
+	res, err := c.get(ctx, "queryrecognizer", query)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error in obtaining full fast query recognizer result")
+	}
+	return body, nil
 }
 
 // PrettyJsonFromRaw returns a formatted JSON string from raw JSON value
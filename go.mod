@@ -6,13 +6,11 @@ require (
 	github.com/johnha/go-wolfram v0.0.0-20180610151123-5b91101b92a8
 	github.com/json-iterator/go v1.1.12
 	github.com/pkg/errors v0.9.1
-	github.com/valyala/fasttemplate v1.2.1
 )
 
 require (
 	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
-	github.com/valyala/bytebufferpool v1.0.0 // indirect
 )
 
 replace github.com/johnha/go-wolfram => /Users/johnha/nodlin/go-wolfram
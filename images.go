@@ -0,0 +1,225 @@
+package wolfram
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// maxImageVariantRequests bounds the number of requests in flight at once when fetching image variants, so that asking
+//	for many widths does not burst past the API rate limits.
+const maxImageVariantRequests = 3
+
+// GetImageVariants requests the query rendered as images at each of the given widths (e.g. 300, 600, 1200) and returns
+//	the images keyed by width, in the manner of an html srcset.   Each width is a separate format=image request with
+//	width and maxwidth set, made in parallel but bounded by maxImageVariantRequests.  No further requests are started
+//	once one has failed, and the first error is returned.
+func (c *Client) GetImageVariants(query string, widths []int, params url.Values) (map[int][]Img, error) {
+	for _, width := range widths {
+		if width <= 0 {
+			return nil, errors.Errorf("image width must be positive (%d)", width)
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		variants = make(map[int][]Img, len(widths))
+		slots    = make(chan struct{}, maxImageVariantRequests)
+	)
+
+	for _, width := range widths {
+		mu.Lock()
+		_, requested := variants[width]
+		failed := firstErr != nil
+		if !requested {
+			variants[width] = nil
+		}
+		mu.Unlock()
+		if failed {
+			break
+		}
+		if requested {
+			continue
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(width int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			variantParams := cloneParams(params)
+			variantParams.Set("format", "image")
+			variantParams.Set("width", strconv.Itoa(width))
+			variantParams.Set("maxwidth", strconv.Itoa(width))
+
+			res, err := c.GetQueryResult(query, variantParams)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = errors.WithMessagef(err, "unable to obtain images of width %d", width)
+				}
+				return
+			}
+			variants[width] = res.Images()
+		}(width)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return variants, nil
+}
+
+// Images returns the images of the result for rendering, those of each subpod followed by those of the infos of each
+//	pod, in the order of the pods.  Images without a src are skipped.
+func (result *QueryResult) Images() []Img {
+	refs := result.imageRefs()
+	images := make([]Img, 0, len(refs))
+	for _, img := range refs {
+		images = append(images, *img)
+	}
+	return images
+}
+
+// imageRefs returns the images of the result in the order of Images, for changing them in place.
+func (result *QueryResult) imageRefs() []*Img {
+	var images []*Img
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		for j := range pod.SubPods {
+			if pod.SubPods[j].Image.Src != "" {
+				images = append(images, &pod.SubPods[j].Image)
+			}
+		}
+		for j := range pod.Infos {
+			for k := range pod.Infos[j].Img {
+				if pod.Infos[j].Img[k].Src != "" {
+					images = append(images, &pod.Infos[j].Img[k])
+				}
+			}
+		}
+	}
+	return images
+}
+
+// maxInlineImageRequests bounds the number of image downloads in flight at once for InlineImages.
+const maxInlineImageRequests = 4
+
+// InlineImages downloads each image of the result (see Images) and replaces its Src with a base64 data URI, so that
+//	the result can be rendered without fetching from Wolfram Alpha, e.g. in an html email.  Downloads are made in
+//	parallel but bounded by maxInlineImageRequests, and an image appearing more than once is downloaded once.   No
+//	further downloads are started once one has failed or ctx is done, and the first error is returned with the images
+//	downloaded so far left inlined.
+func (c *Client) InlineImages(ctx context.Context, result *QueryResult) error {
+	end, err := c.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
+	var srcs []string
+	bySrc := map[string][]*Img{}
+	for _, img := range result.imageRefs() {
+		if strings.HasPrefix(img.Src, "data:") {
+			continue
+		}
+		if _, ok := bySrc[img.Src]; !ok {
+			srcs = append(srcs, img.Src)
+		}
+		bySrc[img.Src] = append(bySrc[img.Src], img)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		slots    = make(chan struct{}, maxInlineImageRequests)
+	)
+
+	for _, src := range srcs {
+		mu.Lock()
+		if firstErr == nil && ctx.Err() != nil {
+			firstErr = ctx.Err()
+		}
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(src string, images []*Img) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			dataURI, err := c.imageDataURI(ctx, src, images[0].ContentType)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = errors.WithMessagef(err, "unable to inline image %s", src)
+				}
+				return
+			}
+			for _, img := range images {
+				img.Src = dataURI
+			}
+		}(src, bySrc[src])
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// imageDataURI downloads the image at src and returns it as a base64 data URI.  The content type is that of the
+//	response, else contentType if given, else detected from the image.  Images are static files rather than requests of
+//	the API, so are downloaded without waiting on the RateLimiter.
+func (c *Client) imageDataURI(ctx context.Context, src string, contentType string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", c.userAgent())
+
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", errors.WithMessage(err, "error in image http request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", errors.Errorf("error downloading image %s, %s", src, res.Status)
+	}
+
+	image, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", errors.WithMessage(err, "error in obtaining image")
+	}
+
+	if responseType := res.Header.Get("Content-Type"); responseType != "" && responseType != "application/octet-stream" {
+		contentType = responseType
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(image)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(image), nil
+}
@@ -0,0 +1,90 @@
+package wolfram
+
+import (
+	"io"
+	"sync"
+)
+
+// lifecycle tracks the operations in flight on a Client so that Close can drain them.   It is held by pointer so that a
+//	Client can be copied; copies share the lifecycle, so closing one closes all.  A Client constructed as a struct
+//	literal has it created on first use (see Client.state), and copies made before then do not share it.
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	closing  chan struct{} // closed by Close to cancel background work, created on first use
+	inFlight sync.WaitGroup
+}
+
+// lifecycleInit guards the creation of the lifecycle of clients constructed without NewClient.
+var lifecycleInit sync.Mutex
+
+// state returns the lifecycle of the client, creating it if the client was constructed as a struct literal.
+func (c *Client) state() *lifecycle {
+	lifecycleInit.Lock()
+	defer lifecycleInit.Unlock()
+
+	if c.lifecycle == nil {
+		c.lifecycle = &lifecycle{}
+	}
+	return c.lifecycle
+}
+
+// begin registers an operation as in flight, returning the function to call when it has finished.   The function may be
+//	called more than once.  ErrClientClosed is returned if the client has been closed.
+func (c *Client) begin() (func(), error) {
+	l := c.state()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil, ErrClientClosed
+	}
+	l.inFlight.Add(1)
+
+	var once sync.Once
+	return func() { once.Do(l.inFlight.Done) }, nil
+}
+
+// closingChannel returns the channel closed when the client is closed, for background work to stop on.
+func (c *Client) closingChannel() <-chan struct{} {
+	l := c.state()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closing == nil {
+		l.closing = make(chan struct{})
+	}
+	return l.closing
+}
+
+// Close shuts the client down for long running services.  Background work held by the client is cancelled, and Close
+//	waits for requests already in flight to finish (for GetSimpleQuery, until the returned body is closed).  Using the
+//	client after Close returns ErrClientClosed.  Closing a client more than once has no effect.
+func (c *Client) Close() error {
+	l := c.state()
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	if l.closing == nil {
+		l.closing = make(chan struct{})
+	}
+	close(l.closing)
+	l.mu.Unlock()
+
+	l.inFlight.Wait()
+	return nil
+}
+
+// trackedBody is a response body that ends its in flight operation when closed.
+type trackedBody struct {
+	io.ReadCloser
+	end func()
+}
+
+func (b *trackedBody) Close() error {
+	defer b.end()
+	return b.ReadCloser.Close()
+}
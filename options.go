@@ -0,0 +1,84 @@
+package wolfram
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// NewClient returns a client for the AppID configured by the options, which are applied in order.   A Client built as a
+//	struct literal continues to work; NewClient is the place for configuration that has no sensible zero value.
+func NewClient(appID string, opts ...Option) *Client {
+	c := &Client{AppID: appID, lifecycle: &lifecycle{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithHTTPClient makes requests with the given http client, e.g. to configure a proxy or transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTimeout limits the time taken by each request, including reading the response.  It applies to the http client
+//	in use at that point (a copy is taken so that a shared client is not changed), so should follow WithHTTPClient.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		httpClient := http.Client{}
+		if c.HTTPClient != nil {
+			httpClient = *c.HTTPClient
+		}
+		httpClient.Timeout = timeout
+		c.HTTPClient = &httpClient
+	}
+}
+
+// WithRequestTimeout limits the time taken by each request, including reading the response, on top of the context of
+//	the request (the earlier deadline taking effect).  Unlike WithTimeout it does not depend on the http client, so may
+//	be given in any order.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.RequestTimeout = timeout
+	}
+}
+
+// WithBaseURL sends requests for the api.wolframalpha.com host to the given base URL, e.g. a mock server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithWebBaseURL sends requests for the www.wolframalpha.com host to the given base URL, e.g. a mock server.
+func WithWebBaseURL(webBaseURL string) Option {
+	return func(c *Client) {
+		c.WebBaseURL = webBaseURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request in place of DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithRateLimiter waits on the limiter before every request, e.g. rate.NewLimiter(rate.Every(time.Second), 1) to make
+//	at most one request a second.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) {
+		c.RateLimiter = limiter
+	}
+}
+
+// WithBatchConcurrency sets the number of queries of a QueryBatch made at once.
+func WithBatchConcurrency(concurrency int) Option {
+	return func(c *Client) {
+		c.BatchConcurrency = concurrency
+	}
+}
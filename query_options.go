@@ -0,0 +1,366 @@
+package wolfram
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// QueryOption sets parameters of a query, returning an error if the values given are invalid.
+type QueryOption func(params url.Values) error
+
+// QueryParams returns the parameters set by the options, which are applied in order.  The parameters can be passed to
+//	any of the query methods taking url.Values, e.g. GetSimpleQuery.
+func QueryParams(opts ...QueryOption) (url.Values, error) {
+	params := url.Values{}
+	for _, opt := range opts {
+		if err := opt(params); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// GetQueryResultWithOptions gets the query result with the parameters set by the options (see QueryParams).
+func (c *Client) GetQueryResultWithOptions(query string, opts ...QueryOption) (*QueryResult, error) {
+	params, err := QueryParams(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.getQueryResult(context.Background(), query, params)
+}
+
+// GetSimpleQueryWithOptions gets an image from the `simple` endpoint with the parameters set by the options, e.g.
+//	WithLayout and WithBackground (see GetSimpleQuery).
+func (c *Client) GetSimpleQueryWithOptions(query string, opts ...QueryOption) (io.ReadCloser, string, error) {
+	params, err := QueryParams(opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.GetSimpleQuery(query, params)
+}
+
+// WithIncludePodIDs requests only the pods with the given IDs, e.g. "Result".  Each ID is sent as its own includepodid
+//	parameter rather than a comma separated list, as IDs may themselves hold commas or other special characters.
+func WithIncludePodIDs(podIDs []string) QueryOption {
+	return func(params url.Values) error {
+		return addPodIDs(params, "includepodid", podIDs)
+	}
+}
+
+// WithExcludePodIDs requests all but the pods with the given IDs, each sent as its own excludepodid parameter.
+func WithExcludePodIDs(podIDs []string) QueryOption {
+	return func(params url.Values) error {
+		return addPodIDs(params, "excludepodid", podIDs)
+	}
+}
+
+// WithPodIndexes requests only the pods at the given positions in the result, counting from 1, e.g. []int{1, 2} for the
+//	first two pods whatever their IDs.  The indexes are sent comma separated as the podindex parameter.
+func WithPodIndexes(indexes []int) QueryOption {
+	return func(params url.Values) error {
+		if len(indexes) == 0 {
+			return errors.New("no pod indexes given")
+		}
+		tokens := make([]string, len(indexes))
+		for i, index := range indexes {
+			if index <= 0 {
+				return errors.Errorf("pod indexes start from 1, got %d", index)
+			}
+			tokens[i] = strconv.Itoa(index)
+		}
+		params.Set("podindex", strings.Join(tokens, ","))
+		return nil
+	}
+}
+
+func addPodIDs(params url.Values, key string, podIDs []string) error {
+	for _, podID := range podIDs {
+		if podID == "" {
+			return errors.Errorf("empty pod id given for %s", key)
+		}
+	}
+	for _, podID := range podIDs {
+		params.Add(key, podID)
+	}
+	return nil
+}
+
+// WithAssumption applies an assumption, the Input of a Value as it was given in the response, e.g.
+//	"*C.dow+chemical-_*Company-".   The input must not be encoded, as it is encoded with the parameters (the '+' as %2B
+//	so that it is not read as a space).  Assumptions of different words may be applied together by giving the option
+//	for each.
+func WithAssumption(input string) QueryOption {
+	return func(params url.Values) error {
+		if input == "" {
+			return errors.New("no assumption input given")
+		}
+		params.Add("assumption", input)
+		return nil
+	}
+}
+
+// Format is a result format requested with the format parameter.
+type Format string
+
+const (
+	FormatPlaintext Format = "plaintext"
+	FormatImage     Format = "image"
+	FormatImageMap  Format = "imagemap"
+	FormatMathML    Format = "mathml"
+	FormatSound     Format = "sound"
+	FormatMinput    Format = "minput"
+	FormatMoutput   Format = "moutput"
+	FormatCell      Format = "cell"
+)
+
+// WithFormats requests the results in the given formats, joined into the comma separated format parameter.  Wolfram
+//	Alpha returns plaintext and image if no format is requested.
+func WithFormats(formats ...Format) QueryOption {
+	return func(params url.Values) error {
+		if len(formats) == 0 {
+			return errors.New("no formats given")
+		}
+		tokens := make([]string, len(formats))
+		for i, format := range formats {
+			if format == "" {
+				return errors.New("empty format given")
+			}
+			tokens[i] = string(format)
+		}
+		params.Set("format", strings.Join(tokens, ","))
+		return nil
+	}
+}
+
+// locationParams are the parameters giving the location of the caller, of which only one may be set.
+var locationParams = []string{"ip", "latlong", "location"}
+
+// WithIP sets the location of the query (e.g. for "weather") to that of the given IP address, rather than the address
+//	the request is made from.   Only one of WithIP, WithLatLong and WithLocation may be used for a query.
+func WithIP(ip string) QueryOption {
+	return func(params url.Values) error {
+		if net.ParseIP(ip) == nil {
+			return errors.Errorf("invalid ip address '%s'", ip)
+		}
+		return setLocation(params, "ip", ip)
+	}
+}
+
+// WithLatLong sets the location of the query to the given latitude and longitude in degrees.   Only one of WithIP,
+//	WithLatLong and WithLocation may be used for a query.
+func WithLatLong(lat, lon float64) QueryOption {
+	return func(params url.Values) error {
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return errors.Errorf("invalid latitude and longitude %g,%g", lat, lon)
+		}
+		latLong := strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+		return setLocation(params, "latlong", latLong)
+	}
+}
+
+// WithLocation sets the location of the query to the named place, e.g. "Boston, MA".   Only one of WithIP,
+//	WithLatLong and WithLocation may be used for a query.
+func WithLocation(location string) QueryOption {
+	return func(params url.Values) error {
+		if strings.TrimSpace(location) == "" {
+			return errors.New("empty location given")
+		}
+		return setLocation(params, "location", location)
+	}
+}
+
+// countryCodePattern matches a two letter ISO 3166-1 country code, e.g. "GB".
+var countryCodePattern = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+// WithCountryCode sets the country of the caller (countrycode) as a two letter ISO 3166-1 code, e.g. "GB", so that
+//	answers follow its conventions such as currency and date formats.  It may be used with any of WithIP, WithLatLong
+//	and WithLocation, which give the place for queries such as "weather", while the country code only sets conventions;
+//	without one of them Wolfram Alpha takes the place from the address the request is made from.   The code is checked
+//	to be two letters, but one that is not assigned to a country is passed through to Wolfram Alpha, which ignores it.
+func WithCountryCode(countryCode string) QueryOption {
+	return func(params url.Values) error {
+		if !countryCodePattern.MatchString(countryCode) {
+			return errors.Errorf("invalid country code '%s', expected two letters e.g. GB", countryCode)
+		}
+		params.Set("countrycode", strings.ToUpper(countryCode))
+		return nil
+	}
+}
+
+// setLocation sets the location parameter key, returning an error if the location has already been given.
+func setLocation(params url.Values, key string, value string) error {
+	for _, locationParam := range locationParams {
+		if params.Get(locationParam) != "" {
+			return errors.Errorf("location already given by %s, unable to also set %s", locationParam, key)
+		}
+	}
+	params.Set(key, value)
+	return nil
+}
+
+// WithScanTimeout sets the time in seconds allowed for the scanners to compute results (scantimeout).  Pods not ready
+//	in time are left out and listed by TimedOut.
+func WithScanTimeout(seconds float64) QueryOption {
+	return timeoutOption("scantimeout", seconds)
+}
+
+// WithPodTimeout sets the time in seconds allowed for formatting each pod (podtimeout).
+func WithPodTimeout(seconds float64) QueryOption {
+	return timeoutOption("podtimeout", seconds)
+}
+
+// WithFormatTimeout sets the time in seconds allowed for formatting all of the pods (formattimeout).
+func WithFormatTimeout(seconds float64) QueryOption {
+	return timeoutOption("formattimeout", seconds)
+}
+
+// WithParseTimeout sets the time in seconds allowed for parsing the query (parsetimeout).  ParseTimedOut is true in the
+//	result if it was exceeded.
+func WithParseTimeout(seconds float64) QueryOption {
+	return timeoutOption("parsetimeout", seconds)
+}
+
+func timeoutOption(key string, seconds float64) QueryOption {
+	return func(params url.Values) error {
+		if seconds <= 0 {
+			return errors.Errorf("%s must be positive, got %g", key, seconds)
+		}
+		params.Set(key, strconv.FormatFloat(seconds, 'f', -1, 64))
+		return nil
+	}
+}
+
+// WithWidth sets the width in pixels at which text in images is wrapped (width).  Also accepted by GetSimpleQuery, for
+//	the width of the whole image.
+func WithWidth(pixels int) QueryOption {
+	return positiveIntOption("width", pixels)
+}
+
+// WithMaxWidth sets the width in pixels up to which images may be widened to avoid wrapping text (maxwidth).
+func WithMaxWidth(pixels int) QueryOption {
+	return positiveIntOption("maxwidth", pixels)
+}
+
+// WithPlotWidth sets the width in pixels of plots and graphics (plotwidth).
+func WithPlotWidth(pixels int) QueryOption {
+	return positiveIntOption("plotwidth", pixels)
+}
+
+// WithMagnification scales the text and graphics of images, 1.0 being the default size (mag).
+func WithMagnification(magnification float64) QueryOption {
+	return func(params url.Values) error {
+		if magnification <= 0 {
+			return errors.Errorf("mag must be positive, got %g", magnification)
+		}
+		params.Set("mag", strconv.FormatFloat(magnification, 'f', -1, 64))
+		return nil
+	}
+}
+
+func positiveIntOption(key string, value int) QueryOption {
+	return func(params url.Values) error {
+		if value <= 0 {
+			return errors.Errorf("%s must be positive, got %d", key, value)
+		}
+		params.Set(key, strconv.Itoa(value))
+		return nil
+	}
+}
+
+// WithUnits sets the unit system of measurements in the result (units).  If not set, Wolfram Alpha chooses by the
+//	location of the query.
+func WithUnits(units Unit) QueryOption {
+	return func(params url.Values) error {
+		value := units.param()
+		if value == "" {
+			return errors.Errorf("unknown unit system %s", units)
+		}
+		params.Set("units", value)
+		return nil
+	}
+}
+
+// Layout is the arrangement of the pods in the image of the simple endpoint, requested with the layout parameter.
+type Layout string
+
+const (
+	LayoutDivider  Layout = "divider"  // pods separated by lines, the default
+	LayoutLabelBar Layout = "labelbar" // each pod with its title in a bar above it
+)
+
+// WithLayout sets the arrangement of the pods in the image of GetSimpleQuery (layout).
+func WithLayout(layout Layout) QueryOption {
+	return func(params url.Values) error {
+		if layout != LayoutDivider && layout != LayoutLabelBar {
+			return errors.Errorf("unknown layout '%s'", layout)
+		}
+		params.Set("layout", string(layout))
+		return nil
+	}
+}
+
+// WithFontSize sets the size in points of the text in the image of GetSimpleQuery (fontsize), 14 by default.
+func WithFontSize(points int) QueryOption {
+	return positiveIntOption("fontsize", points)
+}
+
+// colorPattern matches the colors accepted for the background: a name such as "white", a hex color such as "F5F5F5"
+//	or "#f55" (the '#' is dropped), or red, green and blue values with an optional alpha such as "193,255,255,0.5".
+var colorPattern = regexp.MustCompile(`^(?:[A-Za-z]+|#?[0-9A-Fa-f]{3}|#?[0-9A-Fa-f]{6}|\d{1,3},\d{1,3},\d{1,3}(?:,(?:0|1|0?\.\d+))?)$`)
+
+// WithBackground sets the background color of the image of GetSimpleQuery (background), e.g. "F5F5F5", "black",
+//	"193,255,255" or "transparent".
+func WithBackground(color string) QueryOption {
+	return func(params url.Values) error {
+		if !colorPattern.MatchString(color) {
+			return errors.Errorf("invalid background color '%s'", color)
+		}
+		params.Set("background", strings.TrimPrefix(color, "#"))
+		return nil
+	}
+}
+
+// WithForeground sets the color of the text in the image of GetSimpleQuery (foreground), which may only be "black",
+//	the default, or "white" for use on a dark background.
+func WithForeground(color string) QueryOption {
+	return func(params url.Values) error {
+		color = strings.ToLower(color)
+		if color != "black" && color != "white" {
+			return errors.Errorf("foreground must be black or white, got '%s'", color)
+		}
+		params.Set("foreground", color)
+		return nil
+	}
+}
+
+// WithReinterpret sets whether Wolfram Alpha may reinterpret a query it does not understand as a similar one that it
+//	does (reinterpret), reported in Warnings.ReInterpretations.  If not set the server default applies, which is false.
+func WithReinterpret(reinterpret bool) QueryOption {
+	return boolOption("reinterpret", reinterpret)
+}
+
+// WithTranslation sets whether Wolfram Alpha may translate a query from another language into English (translation),
+//	reported in Warnings.Translations.  If not set the server default applies, which is true.
+func WithTranslation(translation bool) QueryOption {
+	return boolOption("translation", translation)
+}
+
+// WithIgnoreCase sets whether case is ignored when interpreting the query (ignorecase), e.g. so that "mPa" may be read
+//	as "megapascals".   If not set the server default applies, which is false.
+func WithIgnoreCase(ignoreCase bool) QueryOption {
+	return boolOption("ignorecase", ignoreCase)
+}
+
+func boolOption(key string, value bool) QueryOption {
+	return func(params url.Values) error {
+		params.Set(key, strconv.FormatBool(value))
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package wolfram
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Recalculate follows the ReCalculate URL of a result to obtain the pods that timed out when the query was first made,
+//	returning a new result holding the pods of both in position order.  A recalculated pod replaces one of the same
+//	ID.   ErrNothingToRecalculate is returned if the result has no ReCalculate URL.
+func (c *Client) Recalculate(result *QueryResult) (*QueryResult, error) {
+	return c.recalculate(context.Background(), result)
+}
+
+// MaxRecalculateRounds is the most times GetFullResult follows the ReCalculate URL of a result.
+const MaxRecalculateRounds = 3
+
+// GetFullResult runs the query and, while the result has a ReCalculate URL for pods that timed out, recalculates it
+//	(up to MaxRecalculateRounds times), returning the result with the pods of every round.   The result may still have
+//	a ReCalculate URL if Wolfram Alpha had not finished after the last round.   If a round fails the result of the
+//	rounds before it is returned along with the error, so that the pods already obtained are not lost.
+func (c *Client) GetFullResult(ctx context.Context, query string, params url.Values) (*QueryResult, error) {
+	result, err := c.getQueryResult(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	for round := 0; round < MaxRecalculateRounds && result.ReCalculate != ""; round++ {
+		recalculated, err := c.recalculate(ctx, result)
+		if err != nil {
+			return result, err
+		}
+		result = recalculated
+	}
+	return result, nil
+}
+
+// recalculate is Recalculate with a context for the request.
+func (c *Client) recalculate(ctx context.Context, result *QueryResult) (*QueryResult, error) {
+	if result.ReCalculate == "" {
+		return nil, ErrNothingToRecalculate
+	}
+
+	end, err := c.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	recalculateURL, err := url.Parse(result.ReCalculate)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid recalculate url")
+	}
+	query := recalculateURL.Query()
+	query.Set("output", string(OutputJSON))
+	recalculateURL.RawQuery = query.Encode()
+
+	recalculated, _, err := c.fetchQueryResult(ctx, "recalculate", recalculateURL.String(), result.Query, false)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *result
+	merged.Pods = append([]Pod(nil), result.Pods...)
+	for _, pod := range recalculated.Pods {
+		replaced := false
+		for i := range merged.Pods {
+			if pod.ID != "" && merged.Pods[i].ID == pod.ID {
+				merged.Pods[i] = pod
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Pods = append(merged.Pods, pod)
+		}
+	}
+	merged.SortPods()
+	merged.NumPods = len(merged.Pods)
+	merged.TimedOut = recalculated.TimedOut
+	merged.ReCalculate = recalculated.ReCalculate
+	return &merged, nil
+}
@@ -0,0 +1,101 @@
+package wolfram
+
+import (
+	"context"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// simpleImageExtensions are the file extensions for the content types of images from the simple endpoint.
+var simpleImageExtensions = map[string]string{
+	"image/gif":  ".gif",
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+// SimpleImage is an image from the `simple` endpoint with what is needed to pass it on, e.g. as the response to a
+//	browser.   Body must be closed once read.
+type SimpleImage struct {
+	Body          io.ReadCloser
+	ContentType   string // the Content-Type of the response, e.g. "image/gif"
+	ContentLength int64  // the length of the image in bytes, -1 if unknown
+	URL           string // the query url
+}
+
+// GetSimpleImage gets an image from the `simple` endpoint as GetSimpleQuery does, with the request bound to ctx and the
+//	content type and length of the image returned with it.
+func (c *Client) GetSimpleImage(ctx context.Context, query string, params url.Values) (*SimpleImage, error) {
+	res, queryURL, end, err := c.simpleQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// the request remains in flight until the caller has finished with the body
+	return &SimpleImage{
+		Body:          &trackedBody{ReadCloser: res.Body, end: end},
+		ContentType:   res.Header.Get("Content-Type"),
+		ContentLength: res.ContentLength,
+		URL:           queryURL,
+	}, nil
+}
+
+// SaveSimpleQuery gets an image from the `simple` endpoint (see GetSimpleQuery) and writes it to the file at filePath,
+//	returning the query url.  If filePath has no extension, the extension for the content type of the image is added
+//	(.gif, .png or .jpg), so the image is written to e.g. "answer.gif" for "answer".   A partly written file is removed
+//	if the image cannot be read.
+func (c *Client) SaveSimpleQuery(query string, params url.Values, filePath string) (string, error) {
+	res, queryURL, end, err := c.simpleQuery(context.Background(), query, params)
+	if err != nil {
+		return "", err
+	}
+	defer end()
+	defer res.Body.Close()
+
+	if filepath.Ext(filePath) == "" {
+		mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+		if ext, ok := simpleImageExtensions[mediaType]; ok {
+			filePath += ext
+		}
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", errors.WithMessage(err, "unable to create image file")
+	}
+	if _, err := io.Copy(f, res.Body); err != nil {
+		f.Close()
+		os.Remove(filePath)
+		return "", errors.WithMessage(err, "error in obtaining simple query image")
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(filePath)
+		return "", errors.WithMessage(err, "unable to write image file")
+	}
+	return queryURL, nil
+}
+
+// DecodeSimpleQuery gets an image from the `simple` endpoint (see GetSimpleQuery) and decodes it, returning the image
+//	and the name of its format, e.g. "gif".  GIF, JPEG and PNG images are decoded.
+func (c *Client) DecodeSimpleQuery(query string, params url.Values) (image.Image, string, error) {
+	res, _, end, err := c.simpleQuery(context.Background(), query, params)
+	if err != nil {
+		return nil, "", err
+	}
+	defer end()
+	defer res.Body.Close()
+
+	img, format, err := image.Decode(res.Body)
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "unable to decode simple query image")
+	}
+	return img, format, nil
+}
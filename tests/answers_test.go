@@ -0,0 +1,473 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+// resultWithAnswer returns a result holding an input pod and a result pod with the given plaintext.
+func resultWithAnswer(answer string) *wolfram.QueryResult {
+	return &wolfram.QueryResult{
+		Success: true,
+		Pods: []wolfram.Pod{
+			{Title: "Input interpretation", ID: "Input", Position: 100, SubPods: []wolfram.SubPod{{Plaintext: "gold | price"}}},
+			{Title: "Result", ID: "Result", Position: 200, SubPods: []wolfram.SubPod{{Plaintext: answer}}},
+		},
+	}
+}
+
+func TestCompareAnswersUnchanged(t *testing.T) {
+	previous := resultWithAnswer("$1,921.40 per troy  ounce")
+	current := resultWithAnswer(" $1,921.40 PER troy ounce\n")
+
+	change := wolfram.CompareAnswers(previous, current, "")
+	if change.Changed {
+		t.Errorf("expected differences in case and whitespace to be ignored, got %+v", change)
+	}
+}
+
+func TestCompareAnswersChanged(t *testing.T) {
+	previous := resultWithAnswer("$1,921.40 per troy ounce")
+	current := resultWithAnswer("$1,934.15 per troy ounce")
+
+	change := wolfram.CompareAnswers(previous, current, "")
+	if !change.Changed {
+		t.Fatal("expected the answer to have changed")
+	}
+	if change.Old != "$1,921.40 per troy ounce" || change.New != "$1,934.15 per troy ounce" {
+		t.Errorf("unexpected old/new values %+v", change)
+	}
+}
+
+func TestCompareAnswersByPod(t *testing.T) {
+	previous := resultWithAnswer("$1,921.40 per troy ounce")
+	current := resultWithAnswer("$1,934.15 per troy ounce")
+
+	if change := wolfram.CompareAnswers(previous, current, "Input"); change.Changed {
+		t.Errorf("expected the input pod to be unchanged, got %+v", change)
+	}
+	if change := wolfram.CompareAnswers(nil, current, "Result"); !change.Changed || change.Old != "" {
+		t.Errorf("expected a missing previous result to be reported as a change, got %+v", change)
+	}
+}
+
+func TestFirstAnswerFallsBackToPlotAlt(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "plot_only.json"))
+
+	res, err := c.GetQueryResult("plot sin x", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answer, ok := res.FirstAnswer()
+	if !ok || answer != "Plot of sin(x) for x from -6.3 to 6.3" {
+		t.Errorf("expected the plot alt text as the answer, got '%s' (%v)", answer, ok)
+	}
+
+	plots := res.PlotAnswers()
+	if len(plots) != 1 {
+		t.Fatalf("expected 1 plot, got %d", len(plots))
+	}
+	if plots[0].Pod.ID != "Plot" || plots[0].Alt != "Plot of sin(x) for x from -6.3 to 6.3" {
+		t.Errorf("unexpected plot answer %s: %s", plots[0].Pod.ID, plots[0].Alt)
+	}
+}
+
+func TestFirstAnswerPrefersPlaintext(t *testing.T) {
+	res := resultWithAnswer("$1,921.40 per troy ounce")
+	if answer, ok := res.FirstAnswer(); !ok || answer != "$1,921.40 per troy ounce" {
+		t.Errorf("expected the result plaintext, got '%s' (%v)", answer, ok)
+	}
+	if _, ok := (&wolfram.QueryResult{}).FirstAnswer(); ok {
+		t.Error("expected no answer for an empty result")
+	}
+}
+
+func TestResultPrimaryAnswer(t *testing.T) {
+	res := resultWithAnswer("$1,921.40 per troy ounce")
+	if answer, ok := res.PrimaryAnswer(); !ok || answer != "$1,921.40 per troy ounce" {
+		t.Errorf("expected the Result pod plaintext, got '%s' (%v)", answer, ok)
+	}
+
+	solution := &wolfram.QueryResult{Pods: []wolfram.Pod{
+		{Title: "Input", ID: "Input", SubPods: []wolfram.SubPod{{Plaintext: "solve x^2 = 4"}}},
+		{Title: "Solutions", ID: "Solution", SubPods: []wolfram.SubPod{{Plaintext: "x = -2"}, {Plaintext: "x = 2"}}},
+	}}
+	if answer, ok := solution.PrimaryAnswer(); !ok || answer != "x = -2\nx = 2" {
+		t.Errorf("expected the Solution pod plaintext, got '%s' (%v)", answer, ok)
+	}
+
+	data := &wolfram.QueryResult{Pods: []wolfram.Pod{
+		{Title: "Input interpretation", ID: "Input", SubPods: []wolfram.SubPod{{Plaintext: "Paris | population"}}},
+		{Title: "Population", ID: "Population:CityData", Scanner: "Data", SubPods: []wolfram.SubPod{{Plaintext: "2.1 million people"}}},
+	}}
+	if answer, ok := data.PrimaryAnswer(); !ok || answer != "2.1 million people" {
+		t.Errorf("expected the Data scanner pod plaintext, got '%s' (%v)", answer, ok)
+	}
+
+	data.Pods[1].Scanner = "data"
+	if answer, ok := data.PrimaryAnswer(); !ok || answer != "2.1 million people" {
+		t.Errorf("expected the scanner to be matched in any case, got '%s' (%v)", answer, ok)
+	}
+}
+
+func TestResultPrimaryAnswerWithoutAnswerPod(t *testing.T) {
+	res := &wolfram.QueryResult{Pods: []wolfram.Pod{
+		{Title: "Input interpretation", ID: "Input", SubPods: []wolfram.SubPod{{Plaintext: "sin x"}}},
+		{Title: "Plot", ID: "Plot", Scanner: "Plotter", SubPods: []wolfram.SubPod{{Plaintext: ""}}},
+		{Title: "Properties", ID: "Properties", Scanner: "Periodic", SubPods: []wolfram.SubPod{{Plaintext: "periodic in x with period 2 pi"}}},
+	}}
+	if answer, ok := res.PrimaryAnswer(); ok {
+		t.Errorf("expected no primary answer, got '%s'", answer)
+	}
+	if answer, ok := res.FirstAnswer(); !ok || answer != "periodic in x with period 2 pi" {
+		t.Errorf("expected FirstAnswer to fall back to the first non-input pod, got '%s' (%v)", answer, ok)
+	}
+}
+
+func TestVoiceAnswerFromSpokenEndpoint(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/spoken" {
+			t.Errorf("expected only the spoken endpoint to be requested, got %s", r.URL.Path)
+		}
+		w.Write([]byte("The capital of France is Paris \n"))
+	})
+
+	answer, err := c.VoiceAnswer(context.Background(), "capital of France")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "The capital of France is Paris." {
+		t.Errorf("unexpected voice answer '%s'", answer)
+	}
+}
+
+func TestVoiceAnswerFallsBackToPrimaryPod(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/spoken":
+			w.WriteHeader(http.StatusNotImplemented)
+			w.Write([]byte("No spoken result available"))
+		case "/v2/query":
+			w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 2, "pods": [
+				{"title": "Input interpretation", "id": "Input", "position": 100, "subpods": [{"plaintext": "distance | from London to Paris"}]},
+				{"title": "Result", "id": "Result", "position": 200, "subpods": [{"plaintext": "343.9 km  (kilometers);\n213.7 miles"}]}
+			]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	answer, err := c.VoiceAnswer(context.Background(), "distance from London to Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "343.9 kilometers." {
+		t.Errorf("unexpected voice answer '%s'", answer)
+	}
+}
+
+func TestVoiceAnswerWithNoAnswer(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/spoken" {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		w.Write([]byte(`{"queryresult": {"success": false, "error": false, "numpods": 0}}`))
+	})
+
+	if _, err := c.VoiceAnswer(context.Background(), "how do I feel today"); !errors.Is(err, wolfram.ErrNoAnswer) {
+		t.Errorf("expected ErrNoAnswer, got %v", err)
+	}
+}
+
+func TestVoiceAnswerWithResultError(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/spoken" {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		w.Write(fixture(t, "invalid_appid.json"))
+	})
+
+	_, err := c.VoiceAnswer(context.Background(), "capital of France")
+	if err == nil || errors.Is(err, wolfram.ErrNoAnswer) || !strings.Contains(err.Error(), "Invalid appid") {
+		t.Errorf("expected the error reported in the full results, got %v", err)
+	}
+}
+
+func TestVoiceAnswerDoesNotFallBackOnErrors(t *testing.T) {
+	for _, test := range []struct {
+		status   int
+		body     string
+		expected error
+	}{
+		{http.StatusForbidden, "Invalid appid", wolfram.ErrInvalidAppID},
+		{http.StatusTooManyRequests, "Rate limit exceeded", nil},
+	} {
+		requests := 0
+		c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		})
+
+		_, err := c.VoiceAnswer(context.Background(), "capital of France")
+		var apiErr *wolfram.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != test.status {
+			t.Errorf("%d: expected the error of the spoken endpoint, got %v", test.status, err)
+		} else if test.status == http.StatusTooManyRequests && apiErr.RetryAfter != 30*time.Second {
+			t.Errorf("expected the Retry-After of the spoken endpoint, got %v", apiErr.RetryAfter)
+		}
+		if test.expected != nil && !errors.Is(err, test.expected) {
+			t.Errorf("%d: expected %v, got %v", test.status, test.expected, err)
+		}
+		if requests != 1 {
+			t.Errorf("%d: expected no fall back request, got %d requests", test.status, requests)
+		}
+	}
+}
+
+func TestPrimaryAnswer(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := url.Values{
+			"input":        {"dow chemical"},
+			"appid":        {WOLFRAM_APPID},
+			"output":       {"JSON"},
+			"includepodid": {"Result"},
+			"format":       {"plaintext"},
+			"units":        {"metric"},
+		}
+		if !reflect.DeepEqual(r.URL.Query(), expected) {
+			t.Errorf("expected only the parameters for the result pod, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 1,
+			"pods": [{"title": "Result", "id": "Result", "position": 100, "subpods": [{"plaintext": "$52.47"}]}],
+			"assumptions": {"type": "Clash", "word": "dow chemical", "count": 2, "values": [
+				{"name": "Financial", "desc": "a financial entity", "input": "*C.dow+chemical-_*Financial-"},
+				{"name": "Company", "desc": "a company", "input": "*C.dow+chemical-_*Company-"}
+			]}
+		}}`))
+	})
+
+	params := url.Values{}
+	params.Set("units", "metric")
+	params.Set("format", "image")
+	answer, assumed, err := c.PrimaryAnswer(context.Background(), "dow chemical", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "$52.47" || !assumed {
+		t.Errorf("expected an assumed answer of $52.47, got '%s' (%v)", answer, assumed)
+	}
+	if params.Get("format") != "image" {
+		t.Error("expected the caller's parameters to be left unchanged")
+	}
+}
+
+func TestPrimaryAnswerWithoutResultPod(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 0}}`))
+	})
+
+	if _, _, err := c.PrimaryAnswer(context.Background(), "plot sin x", nil); !errors.Is(err, wolfram.ErrNoAnswer) {
+		t.Errorf("expected ErrNoAnswer, got %v", err)
+	}
+}
+
+func TestPrimaryAnswerWithResultError(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "invalid_appid.json"))
+
+	_, _, err := c.PrimaryAnswer(context.Background(), "dow chemical", nil)
+	if err == nil || errors.Is(err, wolfram.ErrNoAnswer) || !strings.Contains(err.Error(), "Invalid appid") {
+		t.Errorf("expected the error reported in the result, got %v", err)
+	}
+}
+
+func TestGetAnswer(t *testing.T) {
+	var requests []string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, query.Get("includepodid"))
+		if query.Get("format") != "plaintext" {
+			t.Errorf("expected plaintext to be requested, got %s", r.URL.RawQuery)
+		}
+		if query.Get("includepodid") == "Result" {
+			w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 0}}`))
+			return
+		}
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 2, "pods": [
+			{"title": "Input", "id": "Input", "position": 100, "subpods": [{"plaintext": "integrate x^2"}]},
+			{"title": "Indefinite integral", "id": "IndefiniteIntegral", "position": 200, "primary": true,
+				"subpods": [{"plaintext": "x^3/3 + constant"}]}
+		]}}`))
+	})
+
+	answer, err := c.GetAnswer(context.Background(), "integrate x^2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "x^3/3 + constant" {
+		t.Errorf("expected the primary pod as the answer, got '%s'", answer)
+	}
+	if !reflect.DeepEqual(requests, []string{"Result", ""}) {
+		t.Errorf("expected the result pod to be requested before all pods, got %q", requests)
+	}
+
+	// there is no answer in either
+	c = mockClient(t, serveFixture(t, "input_only.json"))
+	if _, err := c.GetAnswer(context.Background(), "how do I feel today", nil); !errors.Is(err, wolfram.ErrNoAnswer) {
+		t.Errorf("expected ErrNoAnswer, got %v", err)
+	}
+}
+
+func TestGetAnswerWithResultError(t *testing.T) {
+	requests := 0
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(fixture(t, "invalid_appid.json"))
+	})
+	_, err := c.GetAnswer(context.Background(), "integrate x^2", nil)
+	if err == nil || errors.Is(err, wolfram.ErrNoAnswer) || !strings.Contains(err.Error(), "Invalid appid") {
+		t.Errorf("expected the error reported in the result, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected no fall back query after an error, got %d requests", requests)
+	}
+
+	// an error reported in the result of the fall back query is returned too
+	c = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("includepodid") == "Result" {
+			w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 0}}`))
+			return
+		}
+		w.Write([]byte(`{"queryresult": {"success": false, "error": {"code": "2", "msg": "Appid missing"}, "numpods": 0}}`))
+	})
+	if _, err := c.GetAnswer(context.Background(), "integrate x^2", nil); err == nil || errors.Is(err, wolfram.ErrNoAnswer) {
+		t.Errorf("expected the error reported in the fall back result, got %v", err)
+	}
+}
+
+func TestHasSubstantiveAnswer(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "input_only.json"))
+
+	res, err := c.GetQueryResult("how do I feel today", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Success {
+		t.Fatal("expected the fixture to report success")
+	}
+	if res.HasSubstantiveAnswer() {
+		t.Error("expected an input interpretation alone to not be an answer")
+	}
+
+	if !resultWithAnswer("2").HasSubstantiveAnswer() {
+		t.Error("expected a result pod to be an answer")
+	}
+}
+
+func TestAllPlaintext(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	res, err := c.GetQueryResult("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"1 + 1", "2", "two"}
+	if texts := res.AllPlaintext(); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected %v in position order, got %v", expected, texts)
+	}
+	if ids := podIDs(res); ids[0] != "NumberName" {
+		t.Errorf("expected the pods of the result to be left in their order, got %v", ids)
+	}
+
+	byTitle := map[string][]string{"Input": {"1 + 1"}, "Result": {"2"}, "Number name": {"two"}}
+	if texts := res.PlaintextByTitle(); !reflect.DeepEqual(texts, byTitle) {
+		t.Errorf("expected %v, got %v", byTitle, texts)
+	}
+}
+
+func TestGetSpokenResult(t *testing.T) {
+	body := "\nThe price of gold is about $1,921 per troy ounce\n"
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+
+	result, err := c.GetSpokenResult("price of gold", wolfram.Metric, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Text != "The price of gold is about $1,921 per troy ounce" {
+		t.Errorf("expected the trimmed answer, got '%s'", result.Text)
+	}
+	if !strings.Contains(result.URL, "/v1/spoken?appid=REDACTED&i=price%20of%20gold") {
+		t.Errorf("expected the url requested with the appid redacted, got %s", result.URL)
+	}
+	if answer, err := c.GetSpokenAnswerQuery("price of gold", wolfram.Metric, 0); err != nil || answer != result.Text {
+		t.Errorf("expected the same answer as a string, got '%s' %v", answer, err)
+	}
+
+	body = "  "
+	if _, err := c.GetSpokenResult("price of gold", wolfram.Metric, 0); !errors.Is(err, wolfram.ErrNoShortAnswer) {
+		t.Errorf("expected ErrNoShortAnswer for an empty answer, got %v", err)
+	}
+}
+
+func TestIsSuccessfulAndDidTimeout(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		successful bool
+		timedOut   bool
+	}{
+		{"unordered_pods.json", true, false},
+		{"timed_out.json", true, true},
+		{"plan_required.json", false, false},
+	}
+	for _, test := range tests {
+		res := &wolfram.QueryResult{}
+		data := struct {
+			Result *wolfram.QueryResult `json:"queryresult"`
+		}{res}
+		if err := json.Unmarshal(fixture(t, test.fixture), &data); err != nil {
+			t.Fatalf("%s: %v", test.fixture, err)
+		}
+		if res.IsSuccessful() != test.successful || res.DidTimeout() != test.timedOut {
+			t.Errorf("%s: expected successful %t and timed out %t, got %t and %t", test.fixture,
+				test.successful, test.timedOut, res.IsSuccessful(), res.DidTimeout())
+		}
+	}
+
+	if (&wolfram.QueryResult{Success: true}).IsSuccessful() {
+		t.Error("expected a result without pods to not be successful")
+	}
+}
+
+func TestDataTypeList(t *testing.T) {
+	tests := []struct {
+		dataTypes string
+		expected  []string
+	}{
+		{"", nil},
+		{"Math", []string{"Math"}},
+		{"City,Weather", []string{"City", "Weather"}},
+		{" Financial , Quantity ", []string{"Financial", "Quantity"}},
+	}
+	for _, test := range tests {
+		res := &wolfram.QueryResult{DataTypes: test.dataTypes}
+		if list := res.DataTypeList(); !reflect.DeepEqual(list, test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.dataTypes, test.expected, list)
+		}
+	}
+}
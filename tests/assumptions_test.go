@@ -0,0 +1,509 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+// multiClashAssumption returns the MultiClash assumption of the multiclash fixture.
+func multiClashAssumption(t *testing.T) *wolfram.Assumption {
+	t.Helper()
+	c := mockClient(t, serveFixture(t, "multiclash.json"))
+
+	res, err := c.GetQueryResult("log dow", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Assumptions.Assumption) != 1 {
+		t.Fatalf("expected 1 assumption, got %d", len(res.Assumptions.Assumption))
+	}
+	return &res.Assumptions.Assumption[0]
+}
+
+func TestMultiClashWords(t *testing.T) {
+	assumption := multiClashAssumption(t)
+
+	words, err := assumption.MultiClashWords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 2 {
+		t.Fatalf("expected 2 words, got %d", len(words))
+	}
+
+	expected := map[string][]string{"log": {"Math", "Word"}, "dow": {"Financial", "Person"}}
+	for i, word := range []string{"log", "dow"} {
+		if words[i].Word != word {
+			t.Errorf("expected word %d to be %s, got %s", i, word, words[i].Word)
+		}
+		var names []string
+		for _, option := range words[i].Options {
+			names = append(names, option.Name)
+		}
+		if !reflect.DeepEqual(names, expected[word]) {
+			t.Errorf("expected options %v for %s, got %v", expected[word], word, names)
+		}
+	}
+}
+
+func TestMultiClashParams(t *testing.T) {
+	assumption := multiClashAssumption(t)
+
+	params, err := assumption.MultiClashParams(map[string]string{"dow": "Person", "log": "Word"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"*MC.log-_*Word-", "*MC.~-_*Person-"}
+	if !reflect.DeepEqual(params["assumption"], expected) {
+		t.Errorf("expected assumptions %v, got %v", expected, params["assumption"])
+	}
+
+	if _, err := assumption.MultiClashParams(map[string]string{"dow": "Company"}); err == nil {
+		t.Error("expected an error for an unknown option")
+	}
+	if _, err := assumption.MultiClashParams(map[string]string{"gold": "Financial"}); err == nil {
+		t.Error("expected an error for an unknown word")
+	}
+}
+
+func TestMultiClashWordsRequiresMultiClash(t *testing.T) {
+	assumption := &wolfram.Assumption{Type: "Clash", Word: "dow chemical"}
+	if _, err := assumption.MultiClashWords(); err == nil {
+		t.Error("expected an error for a Clash assumption")
+	}
+}
+
+func TestAssumptionWithSingleValueObject(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "single_value_assumption.json"))
+
+	res, err := c.GetQueryResult("pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Assumptions.Assumption) != 1 {
+		t.Fatalf("expected 1 assumption, got %d", len(res.Assumptions.Assumption))
+	}
+
+	values := res.Assumptions.Assumption[0].Values
+	if len(values) != 1 {
+		t.Fatalf("expected 1 value, got %d", len(values))
+	}
+	if values[0].Name != "NamedConstant" || values[0].Input != "*C.pi-_*NamedConstant-" {
+		t.Errorf("unexpected value %+v", values[0])
+	}
+
+	// a single value is the one assumed, so there is nothing to offer instead
+	if _, err := res.Assumptions.Assumption[0].ForActionDisplay(); err == nil {
+		t.Error("expected nothing to assume with a single value")
+	}
+}
+
+func TestEncodeAssumption(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"*C.dow+chemical-_*Company-", "*C.dow%2Bchemical-_*Company-"},
+		{"*C.pi-_*Movie-", "*C.pi-_*Movie-"},
+		{"*MC.~-_*Financial-", "*MC.~-_*Financial-"},
+		{"DateOrder_**Day.Month.Year--", "DateOrder_**Day.Month.Year--"},
+		{"*FS-_**TemperatureF.TemperatureC--", "*FS-_**TemperatureF.TemperatureC--"},
+		{"*DPClash.UnitE.m-_*Meters-", "*DPClash.UnitE.m-_*Meters-"},
+		{"*F.Tax-_*a=b&c", "*F.Tax-_*a%3Db%26c"},
+	}
+	for _, test := range tests {
+		if encoded := wolfram.EncodeAssumption(test.input); encoded != test.expected {
+			t.Errorf("expected %s to encode as %s, got %s", test.input, test.expected, encoded)
+		}
+		if decoded, err := url.QueryUnescape(wolfram.EncodeAssumption(test.input)); err != nil || decoded != test.input {
+			t.Errorf("expected %s to decode back to itself, got %s (%v)", test.input, decoded, err)
+		}
+	}
+}
+
+func TestAssumptionInputsReceivedExactly(t *testing.T) {
+	inputs := []string{
+		"*C.dow+chemical-_*Company-",
+		"*FS-_**TemperatureF.TemperatureC--",
+		"*DPClash.UnitE.m-_*Meters-",
+		"*MC.~-_*Financial-",
+		"*F.Tax-_*a=b&c",
+		"*C.100%+off-_*Phrase-",
+		"*C.c major-_*MusicalScale-",
+	}
+
+	var received []string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Query()["assumption"]...)
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	for _, input := range inputs {
+		received = nil
+		if _, err := c.GetQueryResultWithAssumption("dow", input, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.GetQueryResultWithOptions("dow", wolfram.WithAssumption(input)); err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{input, input}; !reflect.DeepEqual(received, expected) {
+			t.Errorf("expected the server to receive %q, got %q", expected, received)
+		}
+	}
+
+	if _, err := wolfram.QueryParams(wolfram.WithAssumption("")); err == nil {
+		t.Error("expected an error for an empty assumption")
+	}
+}
+
+func TestEncodePodState(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Result__Step-by-step solution", "Result__Step-by-step%20solution"},
+		{"Population:CityData__More", "Population:CityData__More"},
+		{"DecimalApproximation__More digits", "DecimalApproximation__More%20digits"},
+	}
+	for _, test := range tests {
+		if encoded := wolfram.EncodePodState(test.input); encoded != test.expected {
+			t.Errorf("expected %s to encode as %s, got %s", test.input, test.expected, encoded)
+		}
+	}
+}
+
+func TestGetQueryResultWithAssumption(t *testing.T) {
+	var rawQuery string
+	var query url.Values
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		query = r.URL.Query()
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	params := url.Values{}
+	params.Set("format", "plaintext")
+	if _, err := c.GetQueryResultWithAssumption("dow", "*C.dow+chemical-_*Company-", params); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(rawQuery, "assumption=*C.dow%2Bchemical-_*Company-") {
+		t.Errorf("expected the assumption to be encoded in %s", rawQuery)
+	}
+	if query.Get("assumption") != "*C.dow+chemical-_*Company-" || query.Get("input") != "dow" || query.Get("format") != "plaintext" {
+		t.Errorf("unexpected query %s", rawQuery)
+	}
+
+	if _, err := c.GetQueryResultWithAssumption("dow", "", nil); err == nil {
+		t.Error("expected an error for an empty assumption")
+	}
+}
+
+func TestGetQueryResultWithAssumptions(t *testing.T) {
+	var rawQuery string
+	var query url.Values
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		query = r.URL.Query()
+		w.Write(fixture(t, "multiclash.json"))
+	})
+
+	inputs := []string{"*MC.log-_*Word-", "*MC.~-_*Financial-"}
+	if _, err := c.GetQueryResultWithAssumptions("log dow", inputs, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rawQuery, "assumption=*MC.log-_*Word-&assumption=*MC.~-_*Financial-") {
+		t.Errorf("expected an assumption parameter for each input in %s", rawQuery)
+	}
+	if !reflect.DeepEqual(query["assumption"], inputs) {
+		t.Errorf("expected assumptions %q, got %q", inputs, query["assumption"])
+	}
+
+	if _, err := c.GetQueryResultWithAssumptions("log dow", nil, nil); err == nil {
+		t.Error("expected an error for no assumptions")
+	}
+	if _, err := c.GetQueryResultWithAssumptions("log dow", []string{inputs[0], ""}, nil); err == nil {
+		t.Error("expected an error for an empty assumption")
+	}
+}
+
+func TestGetQueryResultWithPodStates(t *testing.T) {
+	var rawQuery string
+	var query url.Values
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		query = r.URL.Query()
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	states := []string{"Population:CityData__More", "Result__Step-by-step solution"}
+	if _, err := c.GetQueryResultWithPodStates("paris population", states, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(rawQuery, "podstate=Population:CityData__More&podstate=Result__Step-by-step%20solution") {
+		t.Errorf("expected the pod states to be encoded in order in %s", rawQuery)
+	}
+	if !reflect.DeepEqual(query["podstate"], states) {
+		t.Errorf("expected pod states %v, got %v", states, query["podstate"])
+	}
+
+	if _, err := c.GetQueryResultWithPodStates("paris population", []string{""}, nil); err == nil {
+		t.Error("expected an error for an empty pod state")
+	}
+}
+
+func TestInputForValue(t *testing.T) {
+	assumption := multiClashAssumption(t)
+
+	if input, ok := assumption.InputForValue("Financial"); !ok || input != "*MC.~-_*Financial-" {
+		t.Errorf("expected the input of the Financial value, got %q (%t)", input, ok)
+	}
+	if input, ok := assumption.InputForValue("Chemical"); ok {
+		t.Errorf("expected no input for an unknown value, got %q", input)
+	}
+}
+
+func TestAssumptionsMissingOrNull(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 0, "assumptions": null}}`))
+	})
+
+	res, err := c.GetQueryResult("pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Assumptions.Assumption != nil || res.Assumptions.Count != 0 {
+		t.Errorf("expected no assumptions, got %+v", res.Assumptions)
+	}
+
+	for _, data := range []string{"", " ", "null", `{"count": 0}`, `{}`} {
+		assumptions := wolfram.Assumptions{Count: 1, Assumption: make([]wolfram.Assumption, 1)}
+		if err := assumptions.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("%q: %v", data, err)
+		}
+		if assumptions.Assumption != nil || assumptions.Count != 0 {
+			t.Errorf("%q: expected no assumptions, got %+v", data, assumptions)
+		}
+	}
+}
+
+func TestQueryAllAssumptionsWithEmptyWrapper(t *testing.T) {
+	requests := 0
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 0, "assumptions": {"count": 0}}}`))
+	})
+
+	results, err := c.QueryAllAssumptions(context.Background(), "pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || requests != 1 || len(results[0].Assumptions.Assumption) != 0 {
+		t.Errorf("expected only the query without assumptions, got %d results from %d requests", len(results), requests)
+	}
+}
+
+func TestApplyPodStates(t *testing.T) {
+	var requested [][]string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query()["podstate"])
+		w.Write(fixture(t, "pod_states.json"))
+	})
+
+	res, err := c.GetQueryResult("pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod, ok := res.PodByID("DecimalApproximation")
+	if !ok || len(pod.States) != 1 {
+		t.Fatalf("expected the decimal approximation to offer a state, got %+v", pod)
+	}
+
+	// pressing "More digits" twice sends the state twice, the second building on the first
+	for i := 0; i < 2; i++ {
+		pod, _ := res.PodByID("DecimalApproximation")
+		if res, err = c.ApplyPodStates(res, pod.States, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	more := "DecimalApproximation__More digits"
+	expected := [][]string{nil, {more}, {more, more}}
+	if !reflect.DeepEqual(requested, expected) {
+		t.Errorf("expected pod states %q, got %q", expected, requested)
+	}
+	if !reflect.DeepEqual(res.PodStates, []string{more, more}) || res.Query != "pi" {
+		t.Errorf("expected the result to record the query and its pod states, got %q %q", res.Query, res.PodStates)
+	}
+
+	if _, err := c.ApplyPodStates(res, nil, nil); err == nil {
+		t.Error("expected an error for no states")
+	}
+}
+
+func TestForActionDisplay(t *testing.T) {
+	assumption := wolfram.Assumption{
+		Type:     "Clash",
+		Word:     "dow chemical",
+		Template: "Assuming \"${word}\" is ${desc1}. Use as ${desc2} instead of ${desc1}${unknown} ${",
+		Count:    2,
+		Values: wolfram.ValueList{
+			{Name: "Financial", Description: "a financial entity", Input: "*C.dow+chemical-_*Financial-"},
+			{Name: "Company", Description: "a company", Input: "*C.dow+chemical-_*Company-"},
+		},
+	}
+
+	actions, err := assumption.ForActionDisplay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*actions) != 1 {
+		t.Fatalf("expected 1 action, got %d", len(*actions))
+	}
+
+	action := (*actions)[0]
+	expected := "Assuming \"dow chemical\" is a financial entity. Use as a company instead of a financial entity ${"
+	if action.Label != expected {
+		t.Errorf("expected label %q, got %q", expected, action.Label)
+	}
+	if action.Action != "*C.dow+chemical-_*Company-" || action.ButtonLabel != "Company" {
+		t.Errorf("unexpected action %+v", action)
+	}
+}
+
+func TestForActionDisplayAssumptionTypes(t *testing.T) {
+	tests := []struct {
+		assumption wolfram.Assumption
+		expected   []string
+	}{
+		{
+			wolfram.Assumption{
+				Type:     "Unit",
+				Word:     "C",
+				Template: "Assuming ${desc1} for \"${word}\". Use ${desc2} or ${desc3} instead",
+				Count:    3,
+				Values: wolfram.ValueList{
+					{Name: "DegreesCelsius", Description: "degrees Celsius", Input: "UnitClash_*C.*DegreesCelsius--"},
+					{Name: "DegreesFahrenheit", Description: "degrees Fahrenheit", Input: "UnitClash_*C.*DegreesFahrenheit--"},
+					{Name: "Coulombs", Description: "coulombs", Input: "UnitClash_*C.*Coulombs--"},
+				},
+			},
+			[]string{
+				"Assuming degrees Celsius for \"C\". Use degrees Fahrenheit or coulombs instead",
+				"Assuming degrees Celsius for \"C\". Use degrees Fahrenheit or coulombs instead",
+			},
+		},
+		{
+			*multiClashAssumption(t),
+			[]string{
+				"Assuming log is a math function. Use \"log\" as a word instead",
+				"Assuming log is a math function. Use \"dow\" as a financial entity instead",
+				"Assuming log is a math function. Use \"dow\" as a person instead",
+			},
+		},
+		{
+			wolfram.Assumption{
+				Type:     "AngleUnit",
+				Template: "Assuming trigonometric arguments in ${desc1}. Use ${desc2} instead",
+				Count:    2,
+				Values: wolfram.ValueList{
+					{Name: "R", Description: "radians", Input: "TrigRD_R"},
+					{Name: "D", Description: "degrees", Input: "TrigRD_D"},
+				},
+			},
+			[]string{"Assuming trigonometric arguments in radians. Use degrees instead"},
+		},
+	}
+	for _, test := range tests {
+		actions, err := test.assumption.ForActionDisplay()
+		if err != nil {
+			t.Fatalf("%s: %v", test.assumption.Type, err)
+		}
+		var labels []string
+		for _, action := range *actions {
+			labels = append(labels, action.Label)
+		}
+		if !reflect.DeepEqual(labels, test.expected) {
+			t.Errorf("%s: expected labels %q, got %q", test.assumption.Type, test.expected, labels)
+		}
+	}
+}
+
+func TestQueryAllAssumptions(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assumption := r.URL.Query().Get("assumption")
+		if assumption == "" {
+			w.Write(fixture(t, "quirks_array.json"))
+			return
+		}
+		fmt.Fprintf(w, `{"queryresult": {"success": true, "error": false, "numpods": 0, "datatypes": %q}}`, assumption)
+	})
+
+	results, err := c.QueryAllAssumptions(context.Background(), "c major", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected a result for each value of the clash, got %d", len(results))
+	}
+	if results[0].DataTypes != "MusicalNote,Word" || results[1].DataTypes != "*C.c+major-_*MusicalScale-" {
+		t.Errorf("expected the assumed result then the scale, got %s and %s", results[0].DataTypes, results[1].DataTypes)
+	}
+
+	// a query without a clash has only its own result
+	results, err = mockClient(t, serveFixture(t, "multiclash.json")).QueryAllAssumptions(context.Background(), "log dow", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected only the result of the query, got %d", len(results))
+	}
+}
+
+func TestStepByStepSolution(t *testing.T) {
+	var rawQueries []string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQueries = append(rawQueries, r.URL.RawQuery)
+		w.Write(fixture(t, "step_by_step.json"))
+	})
+
+	res, err := c.GetQueryResult("solve x^2 - 4 = 0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod, ok := res.PodByID("Result")
+	if !ok {
+		t.Fatal("expected a result pod")
+	}
+	state, ok := pod.StepByStepState()
+	if !ok || state.Input != "Result__Step-by-step solution" {
+		t.Fatalf("expected the step-by-step state, got %+v (%t)", state, ok)
+	}
+
+	if res, err = c.ApplyPodStates(res, []wolfram.State{state}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rawQueries[1], "&podstate=Result__Step-by-step%20solution") {
+		t.Errorf("expected the step-by-step state to be encoded exactly in %s", rawQueries[1])
+	}
+	if parsed, err := url.ParseQuery(rawQueries[1]); err != nil || parsed.Get("podstate") != state.Input {
+		t.Errorf("expected the pod state to decode back to %q, got %q (%v)", state.Input, parsed.Get("podstate"), err)
+	}
+
+	// states are recognised by name when they are not flagged
+	pod = &wolfram.Pod{States: []wolfram.State{{Name: "Step-by-step solution", Input: "IndefiniteIntegral__Step-by-step solution"}}}
+	if state, ok := pod.StepByStepState(); !ok || state.Input != "IndefiniteIntegral__Step-by-step solution" {
+		t.Errorf("expected the step-by-step state to be found by name, got %+v (%t)", state, ok)
+	}
+	if _, ok := (&wolfram.Pod{States: []wolfram.State{{Name: "More digits"}}}).StepByStepState(); ok {
+		t.Error("expected no step-by-step state")
+	}
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+// asyncHandler serves the async query fixture, with its async URL pointing back at the server, and answers polls of the
+//	async URL with pod, or nothing while pod is nil.
+func asyncHandler(t *testing.T, pod func() []byte) http.HandlerFunc {
+	query := fixture(t, "async_query.json")
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/query":
+			if r.URL.Query().Get("async") != "true" {
+				t.Errorf("expected async=true, got %s", r.URL.RawQuery)
+			}
+			w.Write(bytes.ReplaceAll(query, []byte("{{host}}"), []byte("http://"+r.Host)))
+		case "/api/v1/asyncPod.jsp":
+			if r.URL.Query().Get("id") == "" || r.URL.Query().Get("output") != "JSON" {
+				t.Errorf("unexpected async pod request %s", r.URL.RawQuery)
+			}
+			w.Write(pod())
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+	}
+}
+
+func TestFetchAsyncPods(t *testing.T) {
+	polls := 0
+	c := mockClient(t, asyncHandler(t, func() []byte {
+		// not ready on the first poll
+		polls++
+		if polls == 1 {
+			return nil
+		}
+		return fixture(t, "async_pod.json")
+	}))
+	c.AsyncPollInterval = time.Millisecond
+
+	res, err := c.GetQueryResultAsync("10000!", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pods[1].Async == "" || len(res.Pods[1].SubPods) != 0 {
+		t.Fatalf("expected the second pod to be pending, got %+v", res.Pods[1])
+	}
+
+	if err := c.FetchAsyncPods(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls of the async pod, got %d", polls)
+	}
+
+	pod := res.Pods[1]
+	if pod.Async != "" || pod.ID != "DecimalApproximation" || len(pod.SubPods) != 1 || pod.SubPods[0].Plaintext == "" {
+		t.Errorf("expected the async pod to be filled in, got %+v", pod)
+	}
+	if res.Pods[0].SubPods[0].Plaintext != "10000!" {
+		t.Errorf("expected the other pods to be unchanged, got %+v", res.Pods[0])
+	}
+}
+
+func TestFetchAsyncPodCancelledByContext(t *testing.T) {
+	c := mockClient(t, asyncHandler(t, func() []byte { return nil }))
+	c.AsyncPollInterval = time.Millisecond
+
+	res, err := c.GetQueryResultAsync("10000!", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.FetchAsyncPod(ctx, &res.Pods[1]); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+	if res.Pods[1].Async == "" {
+		t.Error("expected the pod to be left pending")
+	}
+}
+
+func TestCloseCancelsAsyncPolls(t *testing.T) {
+	polled := make(chan struct{}, 1)
+	c := mockClient(t, asyncHandler(t, func() []byte {
+		select {
+		case polled <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+	c.AsyncPollInterval = time.Millisecond
+
+	res, err := c.GetQueryResultAsync("10000!", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetchErr := make(chan error, 1)
+	go func() {
+		fetchErr <- c.FetchAsyncPod(context.Background(), &res.Pods[1])
+	}()
+	<-polled
+
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+
+	select {
+	case err := <-fetchErr:
+		if !errors.Is(err, wolfram.ErrClientClosed) {
+			t.Errorf("expected ErrClientClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to cancel the pending poll")
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to return once the poll was cancelled")
+	}
+}
+
+func TestGetQueryResultStream(t *testing.T) {
+	c := mockClient(t, asyncHandler(t, func() []byte { return fixture(t, "async_pod.json") }))
+	c.AsyncPollInterval = time.Millisecond
+
+	var streamed []string
+	res, err := c.GetQueryResultStream(context.Background(), "10000!", nil, func(pod wolfram.Pod) {
+		if pod.Async != "" || len(pod.SubPods) == 0 {
+			t.Errorf("expected only complete pods, got %+v", pod)
+		}
+		streamed = append(streamed, pod.ID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(streamed) != 2 || streamed[0] != "Input" || streamed[1] != "DecimalApproximation" {
+		t.Errorf("expected the ready pod and then the async pod, got %v", streamed)
+	}
+	if res.Pods[1].Async != "" || len(res.Pods[1].SubPods) != 1 {
+		t.Errorf("expected the result to hold the fetched pod, got %+v", res.Pods[1])
+	}
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+func TestQueryBatch(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		inFlight    int
+		maxInFlight int
+	)
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		input := r.URL.Query().Get("input")
+		if input == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"queryresult": {"success": true, "error": false, "numpods": 1, "pods": [{"title": "Result", "id": "Result", "subpods": [{"plaintext": "%s"}]}]}}`, input)
+	})
+	c.BatchConcurrency = 2
+
+	queries := []string{"a", "b", "fail", "d", "e"}
+	results, errs := c.QueryBatch(context.Background(), queries, nil)
+	if len(results) != len(queries) || len(errs) != len(queries) {
+		t.Fatalf("expected a result and error for each query, got %d and %d", len(results), len(errs))
+	}
+
+	for i, query := range queries {
+		if query == "fail" {
+			var apiErr *wolfram.APIError
+			if results[i] != nil || !errors.As(errs[i], &apiErr) {
+				t.Errorf("expected the failed query to have an APIError, got %v", errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("%s: %v", query, errs[i])
+			continue
+		}
+		if answer, _ := results[i].PrimaryAnswer(); answer != query {
+			t.Errorf("expected the result of '%s' in its place, got '%s'", query, answer)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 queries at once, got %d", maxInFlight)
+	}
+}
+
+func TestQueryBatchCancelled(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, errs := c.QueryBatch(ctx, []string{"1+1", "2+2"}, nil)
+	for i := range results {
+		if results[i] != nil || !errors.Is(errs[i], context.Canceled) {
+			t.Errorf("expected query %d to be cancelled, got %v", i, errs[i])
+		}
+	}
+}
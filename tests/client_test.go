@@ -0,0 +1,524 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+// countingTransport counts the requests made through it.
+type countingTransport struct {
+	requests int
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCustomHTTPClient(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	transport := &countingTransport{}
+	c.HTTPClient = &http.Client{Transport: transport}
+
+	if _, err := c.GetQueryResult("1+1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetShortAnswerQuery("1+1", wolfram.Metric, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetSpokenAnswerQuery("1+1", wolfram.Metric, 0); err != nil {
+		t.Fatal(err)
+	}
+	body, _, err := c.GetSimpleQuery("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+	if _, err := c.GetFastQueryRecognizerRaw("1+1", wolfram.Default); err != nil {
+		t.Fatal(err)
+	}
+
+	if transport.requests != 5 {
+		t.Errorf("expected all 5 requests to use the custom client, got %d", transport.requests)
+	}
+}
+
+// recordingLogger records the messages logged through it.
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestLogger(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "multiclash.json"))
+	logger := &recordingLogger{}
+	c.Logger = logger
+
+	if _, err := c.GetQueryResult("log dow", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.messages) != 1 || !strings.Contains(logger.messages[0], "MultiClash") {
+		t.Errorf("expected the result json to be logged, got %v", logger.messages)
+	}
+}
+
+func TestNoOutputWithoutLogger(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "multiclash.json"))
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	_, queryErr := c.GetQueryResult("log dow", nil)
+	os.Stdout = stdout
+	w.Close()
+
+	output, _ := io.ReadAll(r)
+	if queryErr != nil {
+		t.Fatal(queryErr)
+	}
+	if len(output) != 0 {
+		t.Errorf("expected nothing to be written to stdout, got %s", string(output))
+	}
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	data := fixture(t, "unordered_pods.json")
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	shared := &http.Client{}
+	c := wolfram.NewClient(WOLFRAM_APPID,
+		wolfram.WithHTTPClient(shared),
+		wolfram.WithTimeout(5*time.Second),
+		wolfram.WithBaseURL(server.URL),
+		wolfram.WithUserAgent("dashboard/1.0"),
+	)
+
+	if c.AppID != WOLFRAM_APPID || c.HTTPClient.Timeout != 5*time.Second {
+		t.Errorf("unexpected client configuration %+v", c)
+	}
+	if shared.Timeout != 0 {
+		t.Error("expected the shared http client to be left unchanged")
+	}
+
+	if _, err := c.GetQueryResult("1+1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if userAgent != "dashboard/1.0" {
+		t.Errorf("expected the user agent to be sent, got %s", userAgent)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	var userAgents []string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	if _, err := c.GetQueryResult("1+1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetShortAnswerQuery("1+1", wolfram.Metric, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(userAgents) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(userAgents))
+	}
+	for _, userAgent := range userAgents {
+		if userAgent != "go-wolfram/"+wolfram.Version {
+			t.Errorf("expected the default user agent, got %s", userAgent)
+		}
+	}
+}
+
+func TestNewClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := wolfram.NewClient(WOLFRAM_APPID, wolfram.WithBaseURL(server.URL), wolfram.WithTimeout(20*time.Millisecond))
+	if _, err := c.GetShortAnswerQuery("1+1", wolfram.Metric, 0); err == nil {
+		t.Error("expected the request to time out")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("input") == "slow" {
+			<-release
+		}
+		w.Write(fixture(t, "unordered_pods.json"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := wolfram.NewClient(WOLFRAM_APPID, wolfram.WithBaseURL(server.URL), wolfram.WithRequestTimeout(20*time.Millisecond))
+	if _, err := c.GetFullResult(context.Background(), "slow", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to time out, got %v", err)
+	}
+
+	// the response is read after the request returns, within the timeout
+	if _, err := c.GetFullResult(context.Background(), "fast", nil); err != nil {
+		t.Errorf("expected a quick request to succeed, got %v", err)
+	}
+
+	// an earlier deadline of the caller takes effect
+	c = wolfram.NewClient(WOLFRAM_APPID, wolfram.WithBaseURL(server.URL), wolfram.WithRequestTimeout(time.Minute))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetFullResult(ctx, "slow", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to time out at the deadline of the context, got %v", err)
+	}
+}
+
+func TestContextWithAppID(t *testing.T) {
+	var appIDs []string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		appIDs = append(appIDs, r.URL.Query().Get("appid"))
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	ctx := wolfram.ContextWithAppID(context.Background(), "TENANT-APPID")
+	if _, err := c.GetFullResult(ctx, "paris", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetFullResult(context.Background(), "paris", nil); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"TENANT-APPID", WOLFRAM_APPID}; !reflect.DeepEqual(appIDs, expected) {
+		t.Errorf("expected the AppID of the context over that of the client, got %q", appIDs)
+	}
+
+	// the AppID of the context is checked in place of that of the client
+	c.AppID = ""
+	if _, err := c.GetFullResult(ctx, "paris", nil); err != nil {
+		t.Errorf("expected the AppID of the context to be used without one for the client, got %v", err)
+	}
+	ctx = wolfram.ContextWithAppID(context.Background(), "BAD APPID")
+	if _, err := c.GetFullResult(ctx, "paris", nil); !errors.Is(err, wolfram.ErrInvalidAppID) {
+		t.Errorf("expected ErrInvalidAppID, got %v", err)
+	}
+}
+
+func TestBaseURLRequestEncoding(t *testing.T) {
+	var requests []*url.URL
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	params := url.Values{}
+	params.Set("format", "image,plaintext")
+	params.Add("podstate", "Result__Step-by-step solution")
+
+	if _, err := c.GetQueryResult("solve x^2 + 2x = 1 & x > 0?", params); err != nil {
+		t.Fatal(err)
+	}
+	body, _, err := c.GetSimpleQuery("solve x^2 + 2x = 1 & x > 0?", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	for i, path := range []string{"/v2/query", "/v1/simple"} {
+		query := requests[i].Query()
+		if requests[i].Path != path {
+			t.Errorf("expected path %s, got %s", path, requests[i].Path)
+		}
+		if query.Get("input") != "solve x^2 + 2x = 1 & x > 0?" || query.Get("appid") != WOLFRAM_APPID {
+			t.Errorf("unexpected input or appid in %s", requests[i].RawQuery)
+		}
+		if query.Get("format") != "image,plaintext" || query.Get("podstate") != "Result__Step-by-step solution" {
+			t.Errorf("unexpected extra parameters in %s", requests[i].RawQuery)
+		}
+	}
+}
+
+func TestAnswerEndpointURL(t *testing.T) {
+	var requests []*url.URL
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		w.Write([]byte("about $1,921 per troy ounce"))
+	})
+
+	if _, err := c.GetShortAnswerQuery("price of gold & silver", wolfram.Metric, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetSpokenAnswerQuery("price of gold & silver", wolfram.Imperial, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		path    string
+		units   string
+		timeout string
+	}{
+		{"/v1/result", "metric", "5"},
+		{"/v1/spoken", "imperial", ""},
+	}
+	for i, e := range expected {
+		query := requests[i].Query()
+		if requests[i].Path != e.path {
+			t.Errorf("expected path %s, got %s", e.path, requests[i].Path)
+		}
+		if query.Get("i") != "price of gold & silver" || query.Get("units") != e.units || query.Get("timeout") != e.timeout {
+			t.Errorf("expected the input, units and timeout as separate parameters, got %s", requests[i].RawQuery)
+		}
+		if _, ok := query["output"]; ok {
+			t.Errorf("expected no output parameter for the plaintext endpoint, got %s", requests[i].RawQuery)
+		}
+	}
+}
+
+func TestAnswerEndpointParameterOrder(t *testing.T) {
+	var rawQuery string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte("about $1,921 per troy ounce"))
+	})
+
+	if _, err := c.GetShortAnswerQuery("Price of gold", wolfram.Metric, 5); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "appid=" + WOLFRAM_APPID + "&i=Price%20of%20gold&units=metric&timeout=5"; rawQuery != expected {
+		t.Errorf("expected %s, got %s", expected, rawQuery)
+	}
+}
+
+// countingLimiter counts the waits on it, and blocks until the context is done if block is set.
+type countingLimiter struct {
+	waits int
+	block bool
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	if l.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestRateLimiter(t *testing.T) {
+	requests := 0
+	limiter := &countingLimiter{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(fixture(t, "unordered_pods.json"))
+	}))
+	defer server.Close()
+	c := wolfram.NewClient(WOLFRAM_APPID, wolfram.WithBaseURL(server.URL), wolfram.WithRateLimiter(limiter))
+
+	if _, err := c.GetQueryResult("1+1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetShortAnswerQuery("1+1", wolfram.Metric, 0); err != nil {
+		t.Fatal(err)
+	}
+	if limiter.waits != 2 || requests != 2 {
+		t.Errorf("expected a wait for each of 2 requests, got %d waits for %d requests", limiter.waits, requests)
+	}
+
+	// a cancelled context stops the wait and no request is made
+	limiter.block = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := c.PrimaryAnswer(ctx, "1+1", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to be cancelled, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected no request after the cancelled wait, got %d requests", requests)
+	}
+}
+
+// gzipHandler compresses the responses of handler when the request accepts gzip.
+func gzipHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			handler(w, r)
+			return
+		}
+		recorder := httptest.NewRecorder()
+		handler(recorder, r)
+
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		zw.Write(recorder.Body.Bytes())
+		zw.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(recorder.Code)
+		w.Write(compressed.Bytes())
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	c := mockClient(t, gzipHandler(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/result" {
+			w.WriteHeader(http.StatusNotImplemented)
+			w.Write([]byte("No short answer available"))
+			return
+		}
+		w.Write(fixture(t, "unordered_pods.json"))
+	}))
+
+	res, err := c.GetQueryResult("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pods) != 4 {
+		t.Errorf("expected the compressed result to be decoded, got %d pods", len(res.Pods))
+	}
+
+	// error bodies are decompressed too
+	_, err = c.GetShortAnswerQuery("1+1", wolfram.Metric, 0)
+	var apiErr *wolfram.APIError
+	if !errors.As(err, &apiErr) || apiErr.Body != "No short answer available" {
+		t.Errorf("expected the decompressed error body, got %v", err)
+	}
+
+	// as is a body read by the caller, with compression disabled in the transport
+	c.HTTPClient = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	body, _, err := c.GetSimpleQuery("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil || !bytes.Equal(data, fixture(t, "unordered_pods.json")) {
+		t.Errorf("expected the decompressed body, got %d bytes (%v)", len(data), err)
+	}
+}
+
+func TestShortAnswerTimeout(t *testing.T) {
+	var timeouts []string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		timeouts = append(timeouts, r.URL.Query().Get("timeout"))
+		w.Write([]byte("8 planets"))
+	})
+
+	if _, err := c.GetShortAnswerQueryTimeout("planets", wolfram.Metric, 2500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetShortAnswerQuery("planets", wolfram.Metric, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(timeouts) != 2 || timeouts[0] != "3" || timeouts[1] != "" {
+		t.Errorf("expected timeouts [3 \"\"], got %q", timeouts)
+	}
+
+	if _, err := c.GetShortAnswerQuery("planets", wolfram.Metric, -1); err == nil {
+		t.Error("expected an error for a negative timeout")
+	}
+	if _, err := c.GetShortAnswerQueryTimeout("planets", wolfram.Metric, -time.Second); err == nil {
+		t.Error("expected an error for a negative timeout")
+	}
+	if _, err := c.GetSpokenAnswerQuery("planets", wolfram.Metric, -1); err == nil {
+		t.Error("expected an error for a negative timeout")
+	}
+	if len(timeouts) != 2 {
+		t.Errorf("expected no requests with a negative timeout, got %d", len(timeouts)-2)
+	}
+}
+
+// closeTrackingTransport records whether the body of each response made through it was closed.
+type closeTrackingTransport struct {
+	mu     sync.Mutex
+	bodies []*trackedResponseBody
+}
+
+type trackedResponseBody struct {
+	io.ReadCloser
+	closed bool
+}
+
+func (b *trackedResponseBody) Close() error {
+	b.closed = true
+	return b.ReadCloser.Close()
+}
+
+func (ct *closeTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body := &trackedResponseBody{ReadCloser: res.Body}
+	res.Body = body
+
+	ct.mu.Lock()
+	ct.bodies = append(ct.bodies, body)
+	ct.mu.Unlock()
+	return res, nil
+}
+
+func TestResponseBodiesClosed(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+	transport := &closeTrackingTransport{}
+	c.HTTPClient = &http.Client{Transport: transport}
+
+	requests := map[string]func() error{
+		"GetQueryResult": func() error {
+			_, err := c.GetQueryResult("1+1", nil)
+			return err
+		},
+		"GetQueryResultRaw": func() error {
+			_, _, err := c.GetQueryResultRaw("1+1", nil)
+			return err
+		},
+		"GetQueryResultXML": func() error {
+			_, err := c.GetQueryResultXML("1+1", nil)
+			return err
+		},
+		"GetShortAnswerQuery": func() error {
+			_, err := c.GetShortAnswerQuery("1+1", wolfram.Metric, 0)
+			return err
+		},
+		"GetValidateQuery": func() error {
+			_, err := c.GetValidateQuery("1+1", nil)
+			return err
+		},
+	}
+	for name, request := range requests {
+		transport.bodies = nil
+		if err := request(); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(transport.bodies) != 1 || !transport.bodies[0].closed {
+			t.Errorf("%s: expected the response body to be closed", name)
+		}
+	}
+}
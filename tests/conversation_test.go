@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestConversation(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/v1/conversation.jsp":
+			if query.Get("i") != "How far is Los Angeles from New York?" || query.Get("units") != "metric" {
+				t.Errorf("unexpected first query %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"result": "Los Angeles is about 3970 kilometers from New York", "conversationID": "MSP1411a", "host": "http://%s", "s": "4"}`, r.Host)
+		case "/api/v1/conversation.jsp":
+			if query.Get("i") != "How far is it from Chicago?" || query.Get("conversationid") != "MSP1411a" || query.Get("s") != "4" {
+				t.Errorf("unexpected follow up query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"result": "Los Angeles is about 2810 kilometers from Chicago", "conversationID": "MSP1412b", "host": "www4b.wolframalpha.com"}`))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+	})
+
+	first, err := c.GetConversationalQuery("How far is Los Angeles from New York?", url.Values{"units": {"metric"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Result != "Los Angeles is about 3970 kilometers from New York" || first.ConversationID != "MSP1411a" || first.SInput != "4" {
+		t.Errorf("unexpected first result %+v", first)
+	}
+
+	next, err := c.FollowUpConversation(first, "How far is it from Chicago?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Result != "Los Angeles is about 2810 kilometers from Chicago" || next.ConversationID != "MSP1412b" || next.SInput != "" {
+		t.Errorf("unexpected follow up result %+v", next)
+	}
+}
+
+func TestConversationWithNoAnswer(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": "Wolfram|Alpha did not understand your input"}`))
+	})
+
+	if _, err := c.GetConversationalQuery("fhqwhgads", nil); !errors.Is(err, wolfram.ErrNoAnswer) {
+		t.Errorf("expected ErrNoAnswer, got %v", err)
+	}
+	if _, err := c.ContinueConversation("", "www4b.wolframalpha.com", "and Chicago?"); err == nil {
+		t.Error("expected an error without a conversation id")
+	}
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestDecodeErrorCallback(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "malformed_assumptions.json"))
+
+	var fields []string
+	c.OnDecodeError = func(field string, err error) {
+		if err == nil {
+			t.Errorf("expected an error to be reported for field %s", field)
+		}
+		fields = append(fields, field)
+	}
+
+	res, err := c.GetQueryResult("dow chemical", nil)
+	if err != nil {
+		t.Fatalf("expected the malformed field to be skipped, got %v", err)
+	}
+
+	if len(fields) != 1 || fields[0] != "assumptions" {
+		t.Errorf("expected a single decode error for assumptions, got %v", fields)
+	}
+	if !res.Success || len(res.Pods) != 2 {
+		t.Errorf("expected the rest of the result to decode, got success %v with %d pods", res.Success, len(res.Pods))
+	}
+	if res.Assumptions.Count != 0 {
+		t.Errorf("expected no assumptions, got %d", res.Assumptions.Count)
+	}
+}
+
+func TestDecodeErrorWithoutCallback(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "malformed_assumptions.json"))
+
+	if _, err := c.GetQueryResult("dow chemical", nil); err == nil {
+		t.Error("expected the malformed assumptions to fail the request")
+	}
+}
+
+// decodeFixture decodes the query result of a fixture without a client.
+func decodeFixture(t *testing.T, name string) *wolfram.QueryResult {
+	t.Helper()
+	var data wolfram.Query
+	if err := json.Unmarshal(fixture(t, name), &data); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return &data.Result
+}
+
+// quirkCounts returns the number of entries of each field that Wolfram Alpha gives as an object when there is one.
+func quirkCounts(res *wolfram.QueryResult) map[string]int {
+	pod := res.Pods[1]
+	return map[string]int{
+		"assumptions":     len(res.Assumptions.Assumption),
+		"values":          len(res.Assumptions.Assumption[0].Values),
+		"infos":           len(pod.Infos),
+		"info img":        len(pod.Infos[0].Img),
+		"info links":      len(pod.Infos[0].Link),
+		"info units":      len(pod.Infos[0].Units),
+		"definitions":     len(pod.Definitions),
+		"sounds":          len(pod.Sounds.Sound),
+		"pod sources":     len(pod.Sources.Source),
+		"spellchecks":     len(res.Warnings.Spellchecks),
+		"reinterprets":    len(res.Warnings.ReInterpretations),
+		"alternatives":    len(res.Warnings.ReInterpretations[0].Alternatives),
+		"sources":         len(res.Sources.Source),
+		"generalizations": len(res.Generalizations),
+	}
+}
+
+func TestObjectOrArrayFields(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected map[string]int
+	}{
+		{"quirks_single.json", map[string]int{
+			"assumptions": 1, "values": 1, "infos": 1, "info img": 1, "info links": 1, "info units": 1,
+			"definitions": 1, "sounds": 1, "pod sources": 1, "spellchecks": 1, "reinterprets": 1, "alternatives": 1,
+			"sources": 1, "generalizations": 1,
+		}},
+		{"quirks_array.json", map[string]int{
+			"assumptions": 2, "values": 2, "infos": 2, "info img": 2, "info links": 2, "info units": 2,
+			"definitions": 2, "sounds": 2, "pod sources": 2, "spellchecks": 2, "reinterprets": 1, "alternatives": 2,
+			"sources": 2, "generalizations": 2,
+		}},
+	}
+
+	for _, test := range tests {
+		res := decodeFixture(t, test.fixture)
+		if counts := quirkCounts(res); !reflect.DeepEqual(counts, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.fixture, test.expected, counts)
+		}
+		if res.Assumptions.Count != len(res.Assumptions.Assumption) || res.Sources.Count != len(res.Sources.Source) ||
+			res.Pods[1].Sounds.Count != len(res.Pods[1].Sounds.Sound) {
+			t.Errorf("%s: expected the counts to match the entries, got %d assumptions, %d sources and %d sounds",
+				test.fixture, res.Assumptions.Count, res.Sources.Count, res.Pods[1].Sounds.Count)
+		}
+		// a single entry is decoded in full, not just counted
+		if value := res.Assumptions.Assumption[0].Values[0]; value.Name == "" || value.Input == "" {
+			t.Errorf("%s: expected the assumption value to be decoded, got %+v", test.fixture, value)
+		}
+		if alternative := res.Warnings.ReInterpretations[0].Alternatives[0]; alternative.Value == "" {
+			t.Errorf("%s: expected the alternative to be decoded, got %+v", test.fixture, alternative)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	fixtures := []string{
+		"quirks_single.json", "quirks_array.json", "pod_error.json", "plan_required.json", "unordered_pods.json",
+		"currency_conversion.json", "musical_note.json", "financial_stock.json", "financial_commodity.json",
+		"multiclash.json", "single_value_assumption.json", "timed_out.json", "spellcheck.json", "reinterpret.json",
+		"pod_states.json", "async_query.json", "input_only.json", "plot_only.json", "mathematica_formats.json",
+		"mathml.json", "step_by_step.json",
+	}
+
+	for _, name := range fixtures {
+		res := decodeFixture(t, name)
+		encoded, err := json.Marshal(wolfram.Query{Result: *res})
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		var decoded wolfram.Query
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Errorf("%s: unable to decode the encoded result: %v", name, err)
+			continue
+		}
+		reencoded, err := json.Marshal(decoded)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Errorf("%s: expected the result to be unchanged by encoding\n%s\n%s", name, encoded, reencoded)
+		}
+
+		if !reflect.DeepEqual(decoded.Result.ErrorDetail, res.ErrorDetail) {
+			t.Errorf("%s: expected error detail %+v, got %+v", name, res.ErrorDetail, decoded.Result.ErrorDetail)
+		}
+		for i := range res.Pods {
+			if !reflect.DeepEqual(decoded.Result.Pods[i].ErrorDetail, res.Pods[i].ErrorDetail) {
+				t.Errorf("%s: expected pod %s error detail %+v, got %+v", name, res.Pods[i].ID,
+					res.Pods[i].ErrorDetail, decoded.Result.Pods[i].ErrorDetail)
+			}
+		}
+	}
+}
+
+func TestMathematicaFormats(t *testing.T) {
+	res := decodeFixture(t, "mathematica_formats.json")
+	subPod := res.Pods[0].SubPods[0]
+	if subPod.Minput != "Integrate[x^2, x]" || subPod.Moutput != "x^3/3" {
+		t.Errorf("expected the Wolfram Language input and output, got %q and %q", subPod.Minput, subPod.Moutput)
+	}
+	if subPod := res.Pods[1].SubPods[0]; subPod.Minput != "" || subPod.Moutput != "" {
+		t.Errorf("expected no Wolfram Language forms for the plot, got %q and %q", subPod.Minput, subPod.Moutput)
+	}
+}
+
+func TestMathML(t *testing.T) {
+	res := decodeFixture(t, "mathml.json")
+	for _, pod := range res.Pods {
+		for _, subPod := range pod.SubPods {
+			if !strings.HasPrefix(subPod.MathML, "<math ") || !strings.HasSuffix(subPod.MathML, "</math>") {
+				t.Errorf("expected the MathML of %q, got %q", subPod.Plaintext, subPod.MathML)
+			}
+		}
+	}
+	if mathML := res.Pods[1].SubPods[1].MathML; !strings.Contains(mathML, "<mn>2</mn>") {
+		t.Errorf("expected the MathML of the second solution, got %q", mathML)
+	}
+}
+
+func TestStreamingDecodeMatchesRaw(t *testing.T) {
+	fixtures := []string{
+		"quirks_single.json", "quirks_array.json", "pod_error.json", "unordered_pods.json", "currency_conversion.json",
+		"multiclash.json", "timed_out.json", "spellcheck.json", "reinterpret.json", "mathml.json", "step_by_step.json",
+	}
+
+	for _, name := range fixtures {
+		c := mockClient(t, serveFixture(t, name))
+		streamed, err := c.GetQueryResult("query", nil)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		buffered, body, err := c.GetQueryResultRaw("query", nil)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(body) == 0 {
+			t.Errorf("%s: expected the body to be returned on request", name)
+		}
+		if !reflect.DeepEqual(streamed, buffered) {
+			t.Errorf("%s: expected the streamed result to match the buffered one\n%+v\n%+v", name, streamed, buffered)
+		}
+	}
+
+	// a body cut short is an error rather than a partial result
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fixture(t, "unordered_pods.json")[:200])
+	})
+	if res, err := c.GetQueryResult("query", nil); err == nil {
+		t.Errorf("expected an error for a truncated body, got %+v", res)
+	}
+}
@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+func TestPlanRequiredError(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "plan_required.json"))
+
+	params := url.Values{}
+	params.Add("podstate", "Result__Step-by-step solution")
+
+	res, err := c.GetQueryResult("solve x^2 + 2x + 1 = 0", params)
+	if res != nil {
+		t.Errorf("expected no result for a plan-gated request, got %+v", res)
+	}
+	if !errors.Is(err, wolfram.ErrPlanRequired) {
+		t.Fatalf("expected ErrPlanRequired, got %v", err)
+	}
+
+	feature, ok := wolfram.NeedsPlanUpgrade(err)
+	if !ok || feature != "Step-by-step solution" {
+		t.Errorf("expected feature 'Step-by-step solution', got '%s' (%v)", feature, ok)
+	}
+}
+
+func TestNeedsPlanUpgradeIgnoresOtherErrors(t *testing.T) {
+	if _, ok := wolfram.NeedsPlanUpgrade(errors.New("error in Wolfram Alpha request, Invalid appid (code 1)")); ok {
+		t.Error("expected a non plan error to not need an upgrade")
+	}
+	if _, ok := wolfram.NeedsPlanUpgrade(nil); ok {
+		t.Error("expected nil to not need an upgrade")
+	}
+}
+
+func TestAPIErrorForNon2xxResponse(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Error 1: Invalid appid"))
+	})
+	c.AppID = "NOT-AN-APPID"
+
+	_, err := c.GetQueryResult("1+1", nil)
+
+	var apiErr *wolfram.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.Endpoint != "query" || apiErr.Body != "Error 1: Invalid appid" {
+		t.Errorf("unexpected api error %+v", apiErr)
+	}
+	if strings.Contains(apiErr.URL, "NOT-AN-APPID") || !strings.Contains(apiErr.URL, "appid=REDACTED") {
+		t.Errorf("expected the appid to be redacted from %s", apiErr.URL)
+	}
+}
+
+func TestAPIErrorNamesEndpoint(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("No short answer available"))
+	})
+
+	_, err := c.GetShortAnswerQuery("plot sin x", wolfram.Metric, 0)
+
+	var apiErr *wolfram.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotImplemented || apiErr.Endpoint != "result" {
+		t.Errorf("unexpected api error %+v", apiErr)
+	}
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	retryAt := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"120", 120 * time.Second, 120 * time.Second},
+		{retryAt, 80 * time.Second, 90 * time.Second},
+		{"", 0, 0},
+		{"soon", 0, 0},
+	}
+
+	for _, test := range tests {
+		c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if test.header != "" {
+				w.Header().Set("Retry-After", test.header)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		_, err := c.GetQueryResult("1+1", nil)
+		var apiErr *wolfram.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected an APIError, got %v", err)
+		}
+		if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter < test.min || apiErr.RetryAfter > test.max {
+			t.Errorf("Retry-After '%s': expected a wait between %v and %v, got %v", test.header, test.min, test.max, apiErr.RetryAfter)
+		}
+	}
+}
+
+func TestShortAnswerErrors(t *testing.T) {
+	tests := []struct {
+		status   int
+		body     string
+		expected error
+	}{
+		{http.StatusNotImplemented, "No short answer available", wolfram.ErrNoShortAnswer},
+		{http.StatusForbidden, "Error 1: Invalid appid", wolfram.ErrInvalidAppID},
+		{http.StatusForbidden, "Error 2: Appid missing", wolfram.ErrInvalidAppID},
+	}
+
+	for _, test := range tests {
+		c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		})
+
+		answer, err := c.GetShortAnswerQuery("price of gold", wolfram.Metric, 0)
+		if answer != "" || !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v with no answer, got '%s' %v", test.body, test.expected, answer, err)
+		}
+		if _, err := c.GetSpokenAnswerQuery("price of gold", wolfram.Metric, 0); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v from the spoken endpoint, got %v", test.body, test.expected, err)
+		}
+	}
+
+	// the full results have no short answer, so a 501 there is not reported as one
+	err := &wolfram.APIError{Endpoint: "query", StatusCode: http.StatusNotImplemented}
+	if errors.Is(err, wolfram.ErrNoShortAnswer) || errors.Is(err, wolfram.ErrInvalidAppID) {
+		t.Errorf("unexpected match for %v", err)
+	}
+}
+
+func TestQueryErrorDetail(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"queryresult": {"success": false, "error": {"code": "1", "msg": "Invalid appid"}, "numpods": 0}}`))
+	})
+
+	res, err := c.GetQueryResult("pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ErrorDetail == nil || res.ErrorDetail.Code != 1 || res.ErrorDetail.Msg != "Invalid appid" {
+		t.Errorf("expected code 1 'Invalid appid', got %+v", res.ErrorDetail)
+	}
+	if res.Error.Err == nil {
+		t.Error("expected the error to still be reported")
+	}
+
+	res, err = mockClient(t, serveFixture(t, "unordered_pods.json")).GetQueryResult("pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ErrorDetail != nil {
+		t.Errorf("expected no error detail, got %+v", res.ErrorDetail)
+	}
+}
+
+func TestPodErrorDetail(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "pod_error.json"))
+
+	res, err := c.GetQueryResult("integrate e^(x^7) sin(x) dx", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pods) != 3 {
+		t.Fatalf("expected 3 pods, got %d", len(res.Pods))
+	}
+
+	if pod := res.Pods[0]; pod.Error || pod.ErrorDetail != nil {
+		t.Errorf("expected no error for the input pod, got %v %+v", pod.Error, pod.ErrorDetail)
+	}
+	if pod := res.Pods[1]; !pod.Error || pod.ErrorDetail == nil || pod.ErrorDetail.Code != 3 || pod.ErrorDetail.Msg != "Computation timed out" {
+		t.Errorf("expected code 3 'Computation timed out', got %v %+v", pod.Error, pod.ErrorDetail)
+	}
+	if pod := res.Pods[2]; !pod.Error || pod.ErrorDetail != nil {
+		t.Errorf("expected an error without detail, got %v %+v", pod.Error, pod.ErrorDetail)
+	}
+	if res.Pods[1].Title != "Indefinite integral" || res.Pods[2].Position != 300 {
+		t.Errorf("expected the other fields of the pods to be decoded, got %+v", res.Pods)
+	}
+}
+
+func TestAppIDCheckedBeforeRequest(t *testing.T) {
+	requests := 0
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	c.AppID = ""
+	if _, err := c.GetQueryResult("pi", nil); !errors.Is(err, wolfram.ErrMissingAppID) {
+		t.Errorf("expected ErrMissingAppID, got %v", err)
+	}
+	if _, _, err := c.GetSimpleQuery("pi", nil); !errors.Is(err, wolfram.ErrMissingAppID) {
+		t.Errorf("expected ErrMissingAppID, got %v", err)
+	}
+	if _, err := c.GetShortAnswerQuery("pi", wolfram.Metric, 0); !errors.Is(err, wolfram.ErrMissingAppID) {
+		t.Errorf("expected ErrMissingAppID, got %v", err)
+	}
+
+	c.AppID = "DEMO KEY"
+	if _, err := c.GetQueryResult("pi", nil); !errors.Is(err, wolfram.ErrInvalidAppID) {
+		t.Errorf("expected ErrInvalidAppID, got %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("expected no requests to be made, got %d", requests)
+	}
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	wolfram "wolframAlpha"
+	"wolframAlpha/fakes"
+)
+
+// answerOf is code under test depending on the API rather than a client.
+func answerOf(api wolfram.API, query string) (string, error) {
+	res, err := api.GetQueryResult(query, nil)
+	if err != nil {
+		return "", err
+	}
+	if answer, ok := res.PrimaryAnswer(); ok {
+		return answer, nil
+	}
+	return api.GetShortAnswerQuery(query, wolfram.Metric, 0)
+}
+
+func TestFakeClient(t *testing.T) {
+	fake := &fakes.Client{
+		GetQueryResultFunc: func(query string, params url.Values) (*wolfram.QueryResult, error) {
+			return &wolfram.QueryResult{Success: true}, nil
+		},
+		GetShortAnswerQueryFunc: func(query string, units wolfram.Unit, timeout int) (string, error) {
+			return "2", nil
+		},
+	}
+
+	answer, err := answerOf(fake, "1+1")
+	if err != nil || answer != "2" {
+		t.Errorf("expected the stubbed short answer, got '%s' (%v)", answer, err)
+	}
+	expected := []fakes.Call{{Method: "GetQueryResult", Query: "1+1"}, {Method: "GetShortAnswerQuery", Query: "1+1"}}
+	if calls := fake.Calls(); !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %+v, got %+v", expected, calls)
+	}
+
+	if _, err := fake.GetLLMQuery("1+1", nil); !errors.Is(err, fakes.ErrNotStubbed) {
+		t.Errorf("expected ErrNotStubbed for a method without a function, got %v", err)
+	}
+}
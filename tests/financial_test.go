@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFinancialResult(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		amount   float64
+		currency string
+		asOf     time.Time
+	}{
+		{"financial_stock.json", 1234.56, "USD", time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)},
+		{"financial_commodity.json", 1784.32, "EUR", time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			c := mockClient(t, serveFixture(t, test.fixture))
+
+			res, err := c.GetQueryResult("price", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			amount, currency, asOf, ok := res.FinancialResult()
+			if !ok {
+				t.Fatal("expected a financial result")
+			}
+			if amount != test.amount || currency != test.currency || !asOf.Equal(test.asOf) {
+				t.Errorf("expected %v %s as of %v, got %v %s as of %v",
+					test.amount, test.currency, test.asOf, amount, currency, asOf)
+			}
+		})
+	}
+}
+
+func TestFinancialResultWithCurrencyCode(t *testing.T) {
+	res := resultWithAnswer("1,921.40 GBP per troy ounce")
+
+	amount, currency, asOf, ok := res.FinancialResult()
+	if !ok || amount != 1921.40 || currency != "GBP" || !asOf.IsZero() {
+		t.Errorf("unexpected financial result %v %s %v (%v)", amount, currency, asOf, ok)
+	}
+}
+
+func TestFinancialResultWithoutPrice(t *testing.T) {
+	if _, _, _, ok := resultWithAnswer("Paris, Île-de-France, France").FinancialResult(); ok {
+		t.Error("expected no financial result for an answer without a price")
+	}
+}
@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestGetImageVariants(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]int{}
+
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		width := query.Get("width")
+
+		mu.Lock()
+		requested[width]++
+		mu.Unlock()
+
+		if query.Get("format") != "image" || query.Get("maxwidth") != width {
+			t.Errorf("unexpected variant parameters %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"queryresult": {"success": true, "error": false, "numpods": 1, "pods": [
+			{"title": "Plot", "id": "Plot", "position": 100, "numsubpods": 1, "subpods": [
+				{"title": "", "img": {"src": "https://example.com/plot-%s.gif", "alt": "plot", "width": %s, "height": 100}}
+			]}
+		]}}`, width, width)
+	})
+
+	variants, err := c.GetImageVariants("plot sin x", []int{300, 600, 1200}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(variants) != 3 {
+		t.Fatalf("expected 3 variants, got %d", len(variants))
+	}
+	for _, width := range []int{300, 600, 1200} {
+		images := variants[width]
+		if len(images) != 1 {
+			t.Fatalf("expected 1 image for width %d, got %d", width, len(images))
+		}
+		if images[0].Width != width || images[0].Src != fmt.Sprintf("https://example.com/plot-%d.gif", width) {
+			t.Errorf("unexpected image for width %d: %+v", width, images[0])
+		}
+	}
+	for width, count := range requested {
+		if count != 1 {
+			t.Errorf("expected width %s to be requested once, got %d", width, count)
+		}
+	}
+}
+
+func TestGetImageVariantsRejectsInvalidWidth(t *testing.T) {
+	c := &wolfram.Client{AppID: WOLFRAM_APPID}
+	if _, err := c.GetImageVariants("plot sin x", []int{300, 0}, nil); err == nil {
+		t.Error("expected an error for a zero width")
+	}
+}
+
+func TestImages(t *testing.T) {
+	res, err := mockClient(t, serveFixture(t, "plot_only.json")).GetQueryResult("plot sin x", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := res.Images()
+	if len(images) != 2 || !strings.HasSuffix(images[0].Src, "input.gif") || !strings.HasSuffix(images[1].Src, "plot.gif") {
+		t.Errorf("expected the input and plot images, got %+v", images)
+	}
+
+	// images of infos are included, and pods without images are skipped
+	res, err = mockClient(t, serveFixture(t, "currency_conversion.json")).GetQueryResult("100 gbp in eur", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images = res.Images()
+	if len(images) != 1 || images[0].Alt != "Units" || images[0].Width != 98 {
+		t.Errorf("expected only the units image of the result infos, got %+v", images)
+	}
+}
+
+func TestInlineImages(t *testing.T) {
+	var mu sync.Mutex
+	downloads := map[string]int{}
+
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/query":
+			fmt.Fprintf(w, `{"queryresult": {"success": true, "error": false, "numpods": 2, "pods": [
+				{"title": "Input", "id": "Input", "position": 100, "numsubpods": 1, "subpods": [
+					{"title": "", "img": {"src": "http://%[1]s/images/input.gif", "alt": "x", "contenttype": "image/gif"}}
+				]},
+				{"title": "Plot", "id": "Plot", "position": 200, "numsubpods": 2, "subpods": [
+					{"title": "", "img": {"src": "http://%[1]s/images/plot.png"}},
+					{"title": "", "img": {"src": "http://%[1]s/images/plot.png"}}
+				], "infos": {"img": {"src": "http://%[1]s/images/missing.gif"}}}
+			]}}`, r.Host)
+		case "/images/input.gif":
+			mu.Lock()
+			downloads[r.URL.Path]++
+			mu.Unlock()
+			w.Header().Set("Content-Type", "image/gif")
+			w.Write([]byte("GIF89a"))
+		case "/images/plot.png":
+			mu.Lock()
+			downloads[r.URL.Path]++
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	res, err := c.GetQueryResult("plot x^2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.InlineImages(context.Background(), res)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected the missing image to fail with a 404, got %v", err)
+	}
+	var apiErr *wolfram.APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("expected the missing image not to be an error of the API, got %v", apiErr)
+	}
+
+	images := res.Images()
+	if images[0].Src != "data:image/gif;base64,R0lGODlh" {
+		t.Errorf("expected the gif to be inlined, got %s", images[0].Src)
+	}
+	if images[1].Src != "data:image/png;base64,iVBORw0KGgo=" || images[2].Src != images[1].Src {
+		t.Errorf("expected the png to be inlined with a detected type, got %s and %s", images[1].Src, images[2].Src)
+	}
+	if downloads["/images/plot.png"] != 1 {
+		t.Errorf("expected the repeated image to be downloaded once, got %d", downloads["/images/plot.png"])
+	}
+
+	// inlined images are not downloaded again
+	res.Pods[1].Infos = nil
+	if err := c.InlineImages(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+	if downloads["/images/input.gif"] != 1 {
+		t.Errorf("expected the inlined image to not be downloaded again, got %d", downloads["/images/input.gif"])
+	}
+}
+
+func TestInlineImagesWithoutRateLimiter(t *testing.T) {
+	userAgents := map[string]string{}
+	limiter := &countingLimiter{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents[r.URL.Path] = r.UserAgent()
+		switch r.URL.Path {
+		case "/v2/query":
+			fmt.Fprintf(w, `{"queryresult": {"success": true, "error": false, "numpods": 1, "pods": [
+				{"title": "Input", "id": "Input", "position": 100, "numsubpods": 1, "subpods": [
+					{"title": "", "img": {"src": "http://%s/images/input.gif", "alt": "x", "contenttype": "image/gif"}}
+				]}
+			]}}`, r.Host)
+		case "/images/input.gif":
+			w.Write([]byte("GIF89a"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	c := wolfram.NewClient(WOLFRAM_APPID, wolfram.WithBaseURL(server.URL), wolfram.WithRateLimiter(limiter),
+		wolfram.WithUserAgent("images-test/1.0"))
+
+	res, err := c.GetQueryResult("x", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InlineImages(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+
+	if limiter.waits != 1 {
+		t.Errorf("expected only the query to wait on the limiter, got %d waits", limiter.waits)
+	}
+	if userAgents["/images/input.gif"] != "images-test/1.0" {
+		t.Errorf("expected the image to be downloaded with the user agent, got %q", userAgents["/images/input.gif"])
+	}
+	if src := res.Images()[0].Src; src != "data:image/gif;base64,R0lGODlh" {
+		t.Errorf("expected the gif to be inlined, got %s", src)
+	}
+}
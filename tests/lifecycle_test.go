@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+func TestCloseDrainsInFlightRequests(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	result := fixture(t, "plot_only.json")
+
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.Write(result)
+	})
+
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetQueryResult("plot sin x", nil)
+		requestErr <- err
+	}()
+	<-entered
+
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("expected Close to wait for the request in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-requestErr; err != nil {
+		t.Errorf("expected the request in flight to complete, got %v", err)
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to return once the request completed")
+	}
+}
+
+func TestRequestAfterClose(t *testing.T) {
+	c := &wolfram.Client{AppID: WOLFRAM_APPID}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("expected a second Close to have no effect, got %v", err)
+	}
+
+	if _, err := c.GetQueryResult("1+1", nil); !errors.Is(err, wolfram.ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+	if _, _, err := c.GetSimpleQuery("1+1", nil); !errors.Is(err, wolfram.ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+	if _, err := c.GetShortAnswerQuery("1+1", wolfram.Metric, 0); !errors.Is(err, wolfram.ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+}
+
+func TestCopiedClientSharesClose(t *testing.T) {
+	c := wolfram.NewClient(WOLFRAM_APPID)
+	copied := *c
+	copied.UserAgent = "copied/1.0"
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := copied.GetQueryResult("1+1", nil); !errors.Is(err, wolfram.ErrClientClosed) {
+		t.Errorf("expected the copy to be closed with the client, got %v", err)
+	}
+}
+
+func TestClosedClientWithInvalidAppID(t *testing.T) {
+	c := &wolfram.Client{AppID: "bad app id"}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.GetSimpleQuery("1+1", nil); !errors.Is(err, wolfram.ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed from the simple endpoint, got %v", err)
+	}
+	if _, err := c.GetQueryResult("1+1", nil); !errors.Is(err, wolfram.ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+}
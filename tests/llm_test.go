@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestGetLLMQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v1/llm-api" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if query.Get("appid") != WOLFRAM_APPID || query.Get("input") != "10 densest elemental metals" || query.Get("maxchars") != "500" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Query:\n\"10 densest elemental metals\"\n\nInput interpretation:\n10 densest metallic elements | by mass density\n"))
+	}))
+	defer server.Close()
+
+	c := wolfram.NewClient(WOLFRAM_APPID, wolfram.WithWebBaseURL(server.URL))
+	params := url.Values{}
+	params.Set("maxchars", "500")
+
+	text, err := c.GetLLMQuery("10 densest elemental metals", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Query:\n\"10 densest elemental metals\"\n\nInput interpretation:\n10 densest metallic elements | by mass density\n" {
+		t.Errorf("unexpected llm result %q", text)
+	}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+// mockClient starts a server for handler and returns a client sending all requests to it.
+func mockClient(t *testing.T, handler http.HandlerFunc) *wolfram.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return wolfram.NewClient(WOLFRAM_APPID, wolfram.WithBaseURL(server.URL), wolfram.WithWebBaseURL(server.URL))
+}
+
+// fixture returns the content of a file in testdata.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("unable to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// serveFixture returns a handler that responds to every request with the named fixture.
+func serveFixture(t *testing.T, name string) http.HandlerFunc {
+	data := fixture(t, name)
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
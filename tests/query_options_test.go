@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestPodIDOptions(t *testing.T) {
+	var rawQuery string
+	var query url.Values
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		query = r.URL.Query()
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	include := []string{"Result", "DecimalApproximation", "Plot:1,2"}
+	_, err := c.GetQueryResultWithOptions("pi",
+		wolfram.WithIncludePodIDs(include),
+		wolfram.WithExcludePodIDs([]string{"Input"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(query["includepodid"], include) {
+		t.Errorf("expected includepodid %v, got %v", include, query["includepodid"])
+	}
+	if !strings.Contains(rawQuery, "includepodid=Plot%3A1%2C2") {
+		t.Errorf("expected each pod id to be its own encoded parameter in %s", rawQuery)
+	}
+	if !reflect.DeepEqual(query["excludepodid"], []string{"Input"}) {
+		t.Errorf("expected excludepodid [Input], got %v", query["excludepodid"])
+	}
+}
+
+func TestPodIDOptionsRejectEmptyID(t *testing.T) {
+	if _, err := wolfram.QueryParams(wolfram.WithIncludePodIDs([]string{"Result", ""})); err == nil {
+		t.Error("expected an error for an empty pod id")
+	}
+}
+
+func TestWithPodIndexes(t *testing.T) {
+	params, err := wolfram.QueryParams(wolfram.WithPodIndexes([]int{1, 2, 4}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("podindex") != "1,2,4" {
+		t.Errorf("expected podindex=1,2,4, got %s", params.Encode())
+	}
+
+	for _, indexes := range [][]int{nil, {1, 0}, {-2}} {
+		if _, err := wolfram.QueryParams(wolfram.WithPodIndexes(indexes)); err == nil {
+			t.Errorf("expected an error for pod indexes %v", indexes)
+		}
+	}
+}
+
+func TestWithFormats(t *testing.T) {
+	params, err := wolfram.QueryParams(wolfram.WithFormats(wolfram.FormatPlaintext, wolfram.FormatMathML, wolfram.FormatSound))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("format") != "plaintext,mathml,sound" {
+		t.Errorf("expected format=plaintext,mathml,sound, got %s", params.Encode())
+	}
+
+	if _, err := wolfram.QueryParams(wolfram.WithFormats()); err == nil {
+		t.Error("expected an error for no formats")
+	}
+}
+
+func TestLocationOptions(t *testing.T) {
+	tests := []struct {
+		opt   wolfram.QueryOption
+		key   string
+		value string
+	}{
+		{wolfram.WithIP("192.0.2.10"), "ip", "192.0.2.10"},
+		{wolfram.WithLatLong(40.42, -3.705), "latlong", "40.42,-3.705"},
+		{wolfram.WithLocation("Boston, MA"), "location", "Boston, MA"},
+	}
+	for _, test := range tests {
+		params, err := wolfram.QueryParams(test.opt)
+		if err != nil {
+			t.Errorf("%s: %v", test.key, err)
+			continue
+		}
+		if len(params) != 1 || params.Get(test.key) != test.value {
+			t.Errorf("expected only %s=%s, got %s", test.key, test.value, params.Encode())
+		}
+	}
+}
+
+func TestLocationOptionsAreExclusive(t *testing.T) {
+	if _, err := wolfram.QueryParams(wolfram.WithLocation("Boston, MA"), wolfram.WithLatLong(42.36, -71.06)); err == nil {
+		t.Error("expected an error for more than one location")
+	}
+
+	for _, opt := range []wolfram.QueryOption{wolfram.WithIP("not an ip"), wolfram.WithLatLong(91, 0), wolfram.WithLocation(" ")} {
+		if _, err := wolfram.QueryParams(opt); err == nil {
+			t.Error("expected an error for an invalid location")
+		}
+	}
+}
+
+func TestWithCountryCode(t *testing.T) {
+	params, err := wolfram.QueryParams(wolfram.WithLocation("Boston, MA"), wolfram.WithCountryCode("gb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("countrycode") != "GB" || params.Get("location") != "Boston, MA" {
+		t.Errorf("expected the country code alongside the location, got %s", params.Encode())
+	}
+
+	for _, countryCode := range []string{"", "GBR", "G1", "U S"} {
+		if _, err := wolfram.QueryParams(wolfram.WithCountryCode(countryCode)); err == nil {
+			t.Errorf("expected an error for country code %q", countryCode)
+		}
+	}
+}
+
+func TestTimeoutOptions(t *testing.T) {
+	params, err := wolfram.QueryParams(
+		wolfram.WithScanTimeout(3),
+		wolfram.WithPodTimeout(0.5),
+		wolfram.WithFormatTimeout(8),
+		wolfram.WithParseTimeout(5.25))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"scantimeout":   {"3"},
+		"podtimeout":    {"0.5"},
+		"formattimeout": {"8"},
+		"parsetimeout":  {"5.25"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %s, got %s", expected.Encode(), params.Encode())
+	}
+
+	if _, err := wolfram.QueryParams(wolfram.WithScanTimeout(0)); err == nil {
+		t.Error("expected an error for a timeout that is not positive")
+	}
+}
+
+func TestImageSizeOptions(t *testing.T) {
+	params, err := wolfram.QueryParams(
+		wolfram.WithWidth(300),
+		wolfram.WithMaxWidth(600),
+		wolfram.WithPlotWidth(400),
+		wolfram.WithMagnification(1.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"width":     {"300"},
+		"maxwidth":  {"600"},
+		"plotwidth": {"400"},
+		"mag":       {"1.5"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %s, got %s", expected.Encode(), params.Encode())
+	}
+
+	for _, opt := range []wolfram.QueryOption{wolfram.WithWidth(0), wolfram.WithPlotWidth(-1), wolfram.WithMagnification(0)} {
+		if _, err := wolfram.QueryParams(opt); err == nil {
+			t.Error("expected an error for a size that is not positive")
+		}
+	}
+}
+
+func TestInterpretationOptions(t *testing.T) {
+	params, err := wolfram.QueryParams(
+		wolfram.WithReinterpret(true),
+		wolfram.WithTranslation(false),
+		wolfram.WithIgnoreCase(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"reinterpret": {"true"},
+		"translation": {"false"},
+		"ignorecase":  {"true"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %s, got %s", expected.Encode(), params.Encode())
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestRecalculate(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/query":
+			w.Write(bytes.ReplaceAll(fixture(t, "timed_out.json"), []byte("{{host}}"), []byte("http://"+r.Host)))
+		case "/api/v1/recalc.jsp":
+			if r.URL.Query().Get("id") == "" || r.URL.Query().Get("output") != "JSON" {
+				t.Errorf("unexpected recalculate request %s", r.URL.RawQuery)
+			}
+			w.Write(fixture(t, "recalculated.json"))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+	})
+
+	res, err := c.GetQueryResult("paris", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := c.Recalculate(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Input", "LocalTime:CityData", "Population:CityData", "WeatherObservations:CityData"}
+	if ids := podIDs(full); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected pods %v, got %v", expected, ids)
+	}
+	if full.NumPods != 4 || full.ReCalculate != "" || full.TimedOut != "" {
+		t.Errorf("expected nothing left to recalculate, got %+v", full)
+	}
+	if len(res.Pods) != 2 {
+		t.Error("expected the original result to be left unchanged")
+	}
+
+	if _, err := c.Recalculate(full); !errors.Is(err, wolfram.ErrNothingToRecalculate) {
+		t.Errorf("expected ErrNothingToRecalculate, got %v", err)
+	}
+}
+
+func TestGetFullResult(t *testing.T) {
+	recalculations := 0
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/query":
+			w.Write(bytes.ReplaceAll(fixture(t, "timed_out.json"), []byte("{{host}}"), []byte("http://"+r.Host)))
+		case "/api/v1/recalc.jsp":
+			recalculations++
+			w.Write(fixture(t, "recalculated.json"))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+	})
+
+	full, err := c.GetFullResult(context.Background(), "paris", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Input", "LocalTime:CityData", "Population:CityData", "WeatherObservations:CityData"}
+	if ids := podIDs(full); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected pods %v, got %v", expected, ids)
+	}
+	if recalculations != 1 {
+		t.Errorf("expected the result to be recalculated once, got %d", recalculations)
+	}
+}
+
+func TestGetFullResultRoundsAreBounded(t *testing.T) {
+	recalculations := 0
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/recalc.jsp" {
+			recalculations++
+		}
+		fmt.Fprintf(w, `{"queryresult": {"success": true, "error": false, "numpods": 1, "timedout": "Weather",
+			"recalculate": "http://%s/api/v1/recalc.jsp?id=MSP1&s=12",
+			"pods": [{"title": "Input", "id": "Input", "position": 100, "subpods": [{"plaintext": "Paris"}]}]}}`, r.Host)
+	})
+
+	full, err := c.GetFullResult(context.Background(), "paris", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recalculations != wolfram.MaxRecalculateRounds {
+		t.Errorf("expected %d recalculations, got %d", wolfram.MaxRecalculateRounds, recalculations)
+	}
+	if full.ReCalculate == "" || len(full.Pods) != 1 {
+		t.Errorf("expected the unfinished result with its pod once, got %+v", full)
+	}
+}
+
+func TestGetFullResultKeepsResultWhenRecalculateFails(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/query":
+			w.Write(bytes.ReplaceAll(fixture(t, "timed_out.json"), []byte("{{host}}"), []byte("http://"+r.Host)))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	res, err := c.GetFullResult(context.Background(), "paris", nil)
+	var apiErr *wolfram.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected the error of the recalculate endpoint, got %v", err)
+	}
+	if res == nil || len(res.Pods) != 2 || res.ReCalculate == "" {
+		t.Errorf("expected the result of the query to be returned with the error, got %+v", res)
+	}
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestFastQueryRecognizerRecognized(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "recognizer_recognized.json"))
+
+	res, err := c.GetFastQueryRecognizer("Gold price", wolfram.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Query) != 1 {
+		t.Fatalf("expected 1 query, got %d", len(res.Query))
+	}
+
+	query := res.Query[0]
+	if query.Accepted != "true" || query.Domain != "financial" {
+		t.Errorf("unexpected recognition %+v", query)
+	}
+	if query.SummaryBox == nil || query.SummaryBox.Path == "" {
+		t.Errorf("expected the summary box to be taken from the array, got %+v", query.SummaryBox)
+	}
+	if timing, err := query.TimingFloat(); err != nil || timing != 1.232 {
+		t.Errorf("expected timing 1.232, got %v (%v)", timing, err)
+	}
+	if score, err := query.ResultSignificanceScoreFloat(); err != nil || score != 60 {
+		t.Errorf("expected a significance score of 60, got %v (%v)", score, err)
+	}
+	if _, err := (&wolfram.FastQuery{}).TimingFloat(); err == nil {
+		t.Error("expected an error for a missing timing")
+	}
+}
+
+func TestFastQueryRecognizerUnrecognized(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "recognizer_unrecognized.json"))
+
+	res, err := c.GetFastQueryRecognizer("how do I feel today", wolfram.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Query) != 1 || res.Query[0].Accepted != "false" {
+		t.Fatalf("expected the query to not be accepted, got %+v", res.Query)
+	}
+	if res.Query[0].SummaryBox != nil {
+		t.Errorf("expected no summary box, got %+v", res.Query[0].SummaryBox)
+	}
+}
+
+func TestFastQueryRecognizerRaw(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "recognizer_unrecognized.json"))
+
+	raw, err := c.GetFastQueryRecognizerRaw("how do I feel today", wolfram.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var shape map[string]interface{}
+	if err := json.Unmarshal(raw, &shape); err != nil {
+		t.Fatalf("expected the raw response to be json, got %v", err)
+	}
+	if shape["buildnumber"] != "7501" {
+		t.Errorf("unexpected raw response %s", string(raw))
+	}
+}
+
+func TestFastQueryRecognizerWebBaseURL(t *testing.T) {
+	data := fixture(t, "recognizer_recognized.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/queryrecognizer/query.jsp" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if query.Get("appid") != WOLFRAM_APPID || query.Get("i") != "Gold price" || query.Get("mode") != "Voice" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if query.Get("output") != "json" {
+			t.Errorf("expected json output, got %s", query.Get("output"))
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	c := &wolfram.Client{AppID: WOLFRAM_APPID, WebBaseURL: server.URL + "/"}
+	res, err := c.GetFastQueryRecognizer("Gold price", wolfram.Voice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build, err := res.Build()
+	if err != nil || build != 7501 {
+		t.Errorf("expected build 7501, got %d (%v)", build, err)
+	}
+	major, minor, err := res.VersionNumber()
+	if err != nil || major != 0 || minor != 2 {
+		t.Errorf("expected version 0.2, got %d.%d (%v)", major, minor, err)
+	}
+}
+
+func TestFastQueryRecognizerSpellingCorrection(t *testing.T) {
+	for fixtureName, expected := range map[string]string{
+		"recognizer_spelling.json":   "true",
+		"recognizer_recognized.json": "false",
+	} {
+		c := mockClient(t, serveFixture(t, fixtureName))
+
+		res, err := c.GetFastQueryRecognizer("Gold pirce", wolfram.Default)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.SpellingCorrection != expected {
+			t.Errorf("%s: expected spellingCorrection %s, got '%s'", fixtureName, expected, res.SpellingCorrection)
+		}
+	}
+}
+
+func TestFastQueryRecognizerParams(t *testing.T) {
+	data := fixture(t, "recognizer_recognized.json")
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("units") != "metric" || query.Get("i") != "Gold price" || query.Get("output") != "json" {
+			t.Errorf("expected the parameters to be passed to the recognizer, got %s", r.URL.RawQuery)
+		}
+		w.Write(data)
+	})
+
+	params := url.Values{}
+	params.Set("units", "metric")
+	if _, err := c.GetFastQueryRecognizer("Gold price", wolfram.Default, params); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFastQueryRecognizerReservedParams(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request, got %s", r.URL.RawQuery)
+	})
+
+	for _, key := range []string{"appid", "i", "output", "mode", "Mode"} {
+		params := url.Values{}
+		params.Set(key, "x")
+		if _, err := c.GetFastQueryRecognizer("Gold price", wolfram.Default, url.Values{}, params); !errors.Is(err, wolfram.ErrReservedParam) {
+			t.Errorf("expected ErrReservedParam for %s, got %v", key, err)
+		}
+		if _, err := c.GetFastQueryRecognizerRaw("Gold price", wolfram.Default, params); !errors.Is(err, wolfram.ErrReservedParam) {
+			t.Errorf("expected ErrReservedParam for %s from the raw recognizer, got %v", key, err)
+		}
+	}
+}
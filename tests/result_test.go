@@ -0,0 +1,467 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	wolfram "wolframAlpha"
+)
+
+func podIDs(result *wolfram.QueryResult) []string {
+	ids := make([]string, 0, len(result.Pods))
+	for _, pod := range result.Pods {
+		ids = append(ids, pod.ID)
+	}
+	return ids
+}
+
+func TestSortPods(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	res, err := c.GetQueryResult("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pods[0].ID != "NumberName" {
+		t.Fatalf("expected pods to be left in response order, got %v", podIDs(res))
+	}
+
+	res.SortPods()
+	// pods with equal positions keep their relative order
+	expected := []string{"Input", "Result", "NumberName", "VisualRepresentation"}
+	if !reflect.DeepEqual(podIDs(res), expected) {
+		t.Errorf("expected pods %v, got %v", expected, podIDs(res))
+	}
+}
+
+func TestSortPodsOnDecode(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+	c.SortPodsOnDecode = true
+
+	res, err := c.GetQueryResult("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Input", "Result", "NumberName", "VisualRepresentation"}
+	if !reflect.DeepEqual(podIDs(res), expected) {
+		t.Errorf("expected pods %v, got %v", expected, podIDs(res))
+	}
+}
+
+func TestGetQueryResultRejectsReservedParams(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	for _, key := range []string{"input", "appid", "output", "AppID"} {
+		params := url.Values{}
+		params.Set(key, "XML")
+		if _, err := c.GetQueryResult("1+1", params); !errors.Is(err, wolfram.ErrReservedParam) {
+			t.Errorf("expected ErrReservedParam for %s, got %v", key, err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("format", "plaintext")
+	if _, err := c.GetQueryResult("1+1", params); err != nil {
+		t.Errorf("expected format to be accepted, got %v", err)
+	}
+}
+
+func TestGetQueryResultWithUnits(t *testing.T) {
+	var requests []url.Values
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		w.Write(fixture(t, "unordered_pods.json"))
+	})
+
+	params := url.Values{}
+	params.Set("format", "plaintext")
+	for _, units := range []wolfram.Unit{wolfram.Metric, wolfram.Imperial} {
+		if _, err := c.GetQueryResultWithUnits("distance to the moon", units, params); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, expected := range []string{"metric", "imperial"} {
+		if requests[i].Get("units") != expected || requests[i].Get("format") != "plaintext" {
+			t.Errorf("expected units=%s with the extra parameters, got %s", expected, requests[i].Encode())
+		}
+	}
+	if params.Get("units") != "" {
+		t.Error("expected the caller's params to be left unchanged")
+	}
+
+	params.Set("units", "metric")
+	if _, err := c.GetQueryResultWithUnits("distance to the moon", wolfram.Imperial, params); !errors.Is(err, wolfram.ErrReservedParam) {
+		t.Errorf("expected ErrReservedParam for units in params, got %v", err)
+	}
+
+	if _, err := c.GetQueryResultWithUnits("distance to the moon", wolfram.Unit(7), nil); err == nil ||
+		!strings.Contains(err.Error(), "unknown unit system") {
+		t.Errorf("expected an error for an unknown unit system, got %v", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("expected no request with an unknown unit system, got %d requests", len(requests))
+	}
+}
+
+func TestPrimaryPods(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "financial_stock.json"))
+
+	res, err := c.GetQueryResult("AAPL price", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := res.PrimaryPods()
+	if len(primary) != 1 || primary[0].ID != "Result" {
+		t.Fatalf("expected the Result pod to be primary, got %+v", primary)
+	}
+	if pods := (&wolfram.QueryResult{Pods: []wolfram.Pod{{ID: "Input"}}}).PrimaryPods(); len(pods) != 0 {
+		t.Errorf("expected no primary pods, got %+v", pods)
+	}
+}
+
+func TestPrimaryAnswerPrefersPrimaryPod(t *testing.T) {
+	res := &wolfram.QueryResult{Pods: []wolfram.Pod{
+		{Title: "Result", ID: "Result", SubPods: []wolfram.SubPod{{Plaintext: "1/2"}}},
+		{Title: "Decimal approximation", ID: "DecimalApproximation", Primary: true, SubPods: []wolfram.SubPod{{Plaintext: "0.5"}}},
+	}}
+	if answer, ok := res.PrimaryAnswer(); !ok || answer != "0.5" {
+		t.Errorf("expected the primary pod plaintext, got '%s' (%v)", answer, ok)
+	}
+}
+
+func TestSources(t *testing.T) {
+	tests := []struct {
+		fixture string
+		texts   []string
+	}{
+		{"financial_stock.json", []string{"Financial data", "Company data"}},
+		{"financial_commodity.json", []string{"Commodity data"}},
+		{"single_value_assumption.json", nil},
+	}
+
+	for _, test := range tests {
+		c := mockClient(t, serveFixture(t, test.fixture))
+
+		res, err := c.GetQueryResult("price", nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.fixture, err)
+		}
+
+		var texts []string
+		for _, source := range res.Sources.Source {
+			if source.URL == "" {
+				t.Errorf("%s: expected a URL for source '%s'", test.fixture, source.Text)
+			}
+			texts = append(texts, source.Text)
+		}
+		if !reflect.DeepEqual(texts, test.texts) || res.Sources.Count != len(test.texts) {
+			t.Errorf("%s: expected sources %v, got %v (count %d)", test.fixture, test.texts, texts, res.Sources.Count)
+		}
+	}
+}
+
+func TestSourcesWithoutEntries(t *testing.T) {
+	for _, data := range []string{`{"count": 0}`, `{"count": 0, "source": null}`} {
+		sources := wolfram.Sources{Count: 1, Source: make([]wolfram.Source, 1)}
+		if err := sources.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if sources.Count != 0 || len(sources.Source) != 0 {
+			t.Errorf("%s: expected no sources, got %+v", data, sources)
+		}
+	}
+}
+
+func TestSourceLinks(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "numpods": 2,
+			"sources": {"count": 2, "source": [
+				{"url": "https://www.wolframalpha.com/sources/CityDataSourceInformationNotes.html", "text": "City data"},
+				{"url": "https://www.wolframalpha.com/sources/WeatherDataSourceInformationNotes.html", "text": "Weather data"}
+			]},
+			"pods": [
+				{"title": "Population", "id": "Population", "sources": {"count": 1, "source":
+					{"url": "https://www.wolframalpha.com/sources/CityDataSourceInformationNotes.html", "text": "City data"}}},
+				{"title": "Economic properties", "id": "Economy", "sources":
+					{"url": "https://www.wolframalpha.com/sources/EconomicDataSourceInformationNotes.html", "text": "Economic data"}}
+			]}}`))
+	})
+
+	res, err := c.GetQueryResult("paris", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sources.Count != 2 || res.Pods[0].Sources.Count != 1 {
+		t.Errorf("expected the wrapped sources to be decoded, got %+v and %+v", res.Sources, res.Pods[0].Sources)
+	}
+
+	var texts []string
+	for _, source := range res.SourceLinks() {
+		texts = append(texts, source.Text)
+	}
+	expected := []string{"City data", "Weather data", "Economic data"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected source links %v, got %v", expected, texts)
+	}
+}
+
+func TestInfos(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "currency_conversion.json"))
+
+	res, err := c.GetQueryResult("convert £100 to euros", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pods) != 3 {
+		t.Fatalf("expected 3 pods, got %d", len(res.Pods))
+	}
+
+	// a single info object holding the units legend
+	infos := res.Pods[1].Infos
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 info for the result pod, got %d", len(infos))
+	}
+	expectedUnits := []wolfram.InfoUnit{{Short: "£", Long: "British pounds"}, {Short: "€", Long: "euros"}}
+	if !reflect.DeepEqual(infos[0].Units, expectedUnits) {
+		t.Errorf("expected units %+v, got %+v", expectedUnits, infos[0].Units)
+	}
+	if len(infos[0].Img) != 1 || infos[0].Img[0].Alt != "Units" {
+		t.Errorf("expected the units image, got %+v", infos[0].Img)
+	}
+
+	// an array of infos, with a single link and an array of links
+	infos = res.Pods[2].Infos
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 infos for the history pod, got %d", len(infos))
+	}
+	if infos[0].Text != "Rates are updated hourly" || len(infos[0].Link) != 1 || infos[0].Link[0].Text != "Currency data" {
+		t.Errorf("unexpected info %+v", infos[0])
+	}
+	if len(infos[1].Link) != 2 || infos[1].Link[1].URL != "https://mathworld.wolfram.com/" {
+		t.Errorf("unexpected info links %+v", infos[1].Link)
+	}
+
+	if len(res.Pods[0].Infos) != 0 {
+		t.Errorf("expected no infos for the input pod, got %+v", res.Pods[0].Infos)
+	}
+}
+
+func TestSounds(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "musical_note.json"))
+
+	res, err := c.GetQueryResult("middle C", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sounds := res.Pods[1].Sounds
+	if sounds.Count != 1 || len(sounds.Sound) != 1 || sounds.Sound[0].Type != "audio/midi" || sounds.Sound[0].URL == "" {
+		t.Errorf("expected a midi sound, got %+v", sounds)
+	}
+	if res.Pods[0].Sounds.Count != 0 {
+		t.Errorf("expected no sounds for the input pod, got %+v", res.Pods[0].Sounds)
+	}
+
+	tests := []struct {
+		json  string
+		types []string
+	}{
+		{`{"count": 2, "sound": [{"url": "a", "type": "audio/midi"}, {"url": "b", "type": "audio/wav"}]}`, []string{"audio/midi", "audio/wav"}},
+		{`[{"url": "a", "type": "audio/midi"}, {"url": "b", "type": "audio/wav"}]`, []string{"audio/midi", "audio/wav"}},
+		{`{"url": "a", "type": "audio/wav"}`, []string{"audio/wav"}},
+	}
+	for _, test := range tests {
+		var sounds wolfram.Sounds
+		if err := sounds.UnmarshalJSON([]byte(test.json)); err != nil {
+			t.Errorf("%s: %v", test.json, err)
+			continue
+		}
+		var types []string
+		for _, sound := range sounds.Sound {
+			types = append(types, sound.Type)
+		}
+		if !reflect.DeepEqual(types, test.types) || sounds.Count != len(test.types) {
+			t.Errorf("%s: expected sound types %v, got %v (count %d)", test.json, test.types, types, sounds.Count)
+		}
+	}
+}
+
+func TestSoundsWithoutEntries(t *testing.T) {
+	for _, data := range []string{`{"count": 0}`, `{"count": 0, "sound": null}`} {
+		var sounds wolfram.Sounds
+		if err := sounds.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if sounds.Count != 0 || len(sounds.Sound) != 0 {
+			t.Errorf("%s: expected no sounds, got %+v", data, sounds)
+		}
+	}
+
+	var res wolfram.QueryResult
+	if err := json.Unmarshal([]byte(`{"pods": [{"id": "Input", "sounds": {"count": 0}}]}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	if sounds := res.Pods[0].Sounds; sounds.Count != 0 || len(sounds.Sound) != 0 {
+		t.Errorf("expected no sounds for the pod, got %+v", sounds)
+	}
+}
+
+func TestTimingDurations(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "financial_stock.json"))
+
+	res, err := c.GetQueryResult("AAPL price", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := res.TimingDuration(); d != 1977*time.Millisecond {
+		t.Errorf("expected 1.977s, got %v", d)
+	}
+	if d := res.ParseTimingDuration(); d != 283*time.Millisecond {
+		t.Errorf("expected 283ms, got %v", d)
+	}
+	if d := (&wolfram.QueryResult{}).TimingDuration(); d != 0 {
+		t.Errorf("expected no timing, got %v", d)
+	}
+}
+
+func TestPodLookup(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	res, err := c.GetQueryResult("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pod, ok := res.PodByID("Result"); !ok || pod.Title != "Result" {
+		t.Errorf("expected the Result pod, got %+v (%v)", pod, ok)
+	}
+	if _, ok := res.PodByID("result"); ok {
+		t.Error("expected pod ids to be matched exactly")
+	}
+
+	pod, ok := res.PodByTitle("  number NAME ")
+	if !ok || pod.ID != "NumberName" {
+		t.Fatalf("expected the Number name pod, got %+v (%v)", pod, ok)
+	}
+	pod.Title = "Changed"
+	if res.Pods[0].Title != "Changed" {
+		t.Error("expected the pod returned to be that of the result")
+	}
+
+	if _, ok := res.PodByTitle("Plot"); ok {
+		t.Error("expected no Plot pod")
+	}
+}
+
+func TestGetQueryResultRaw(t *testing.T) {
+	data := fixture(t, "unordered_pods.json")
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	res, raw, err := c.GetQueryResultRaw("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pods) != 4 {
+		t.Errorf("expected the parsed result with 4 pods, got %d", len(res.Pods))
+	}
+	if !bytes.Equal(raw, data) {
+		t.Error("expected the raw body to be the response")
+	}
+
+	// fields not modelled by QueryResult can be taken from the raw body
+	unmodelled := struct {
+		Result struct {
+			Host string `json:"host"`
+		} `json:"queryresult"`
+	}{}
+	if err := json.Unmarshal(raw, &unmodelled); err != nil || unmodelled.Result.Host == "" {
+		t.Errorf("expected the host from the raw body, got '%s' %v", unmodelled.Result.Host, err)
+	}
+
+	c = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"queryresult": {"pods": "not pods"}}`))
+	})
+	if _, raw, err := c.GetQueryResultRaw("1+1", nil); err == nil || len(raw) == 0 {
+		t.Errorf("expected the raw body with the decode error, got '%s' %v", raw, err)
+	}
+}
+
+func TestGetQueryResultXML(t *testing.T) {
+	const xmlResult = `<?xml version='1.0' encoding='UTF-8'?>
+<queryresult success='true' error='false' numpods='1' version='2.6'>
+ <pod title='Result' scanner='Simplification' id='Result' position='200' error='false' numsubpods='1' primary='true'>
+  <subpod title=''>
+   <plaintext>2</plaintext>
+  </subpod>
+ </pod>
+</queryresult>`
+
+	var output string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		output = r.URL.Query().Get("output")
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(xmlResult))
+	})
+
+	result, err := c.GetQueryResultXML("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "XML" {
+		t.Errorf("expected output=XML, got %s", output)
+	}
+	if result != xmlResult {
+		t.Errorf("expected the XML as received, got %s", result)
+	}
+
+	params := url.Values{}
+	params.Set("output", "JSON")
+	if _, err := c.GetQueryResultXML("1+1", params); !errors.Is(err, wolfram.ErrReservedParam) {
+		t.Errorf("expected ErrReservedParam for output, got %v", err)
+	}
+}
+
+func TestPodsByScanner(t *testing.T) {
+	res, err := mockClient(t, serveFixture(t, "unordered_pods.json")).GetQueryResult("42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"NumberName", "VisualRepresentation"}
+	if ids := podIDs(&wolfram.QueryResult{Pods: res.PodsByScanner("integer")}); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected pods %v, got %v", expected, ids)
+	}
+	if pods := res.PodsByScanner("Data"); len(pods) != 0 {
+		t.Errorf("expected no pods from the Data scanner, got %d", len(pods))
+	}
+
+	res.Pods = append(res.Pods, wolfram.Pod{ID: "Extra", Scanner: "INTEGER"}, wolfram.Pod{ID: "Unknown"})
+	expected = []string{"Integer", "Identity", "Simplification"}
+	if scanners := res.Scanners(); !reflect.DeepEqual(scanners, expected) {
+		t.Errorf("expected scanners %v, got %v", expected, scanners)
+	}
+}
+
+func TestResultQueryIsUnescaped(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "unordered_pods.json"))
+
+	for _, query := range []string{"1 + 1 = ?", "dow chemical", "café & co"} {
+		res, err := c.GetQueryResult(query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Query != query {
+			t.Errorf("expected the query as it was given, %q, got %q", query, res.Query)
+		}
+	}
+}
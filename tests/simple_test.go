@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+// gifImage is a 1x1 GIF.
+var gifImage = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\xff\xff\xff\x00\x00\x00!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
+func serveGIF(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "image/gif")
+	w.Write(gifImage)
+}
+
+func TestSaveSimpleQuery(t *testing.T) {
+	c := mockClient(t, serveGIF)
+	dir := t.TempDir()
+
+	queryURL, err := c.SaveSimpleQuery("1+1", nil, filepath.Join(dir, "answer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(queryURL, "/v1/simple?") {
+		t.Errorf("expected the simple query url, got %s", queryURL)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "answer.gif"))
+	if err != nil {
+		t.Fatalf("expected the image to be saved with a .gif extension: %v", err)
+	}
+	if !bytes.Equal(data, gifImage) {
+		t.Error("expected the saved image to be the response body")
+	}
+
+	// an extension given is kept whatever the content type
+	if _, err := c.SaveSimpleQuery("1+1", nil, filepath.Join(dir, "answer.img")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "answer.img")); err != nil {
+		t.Errorf("expected the image to be saved to the path given: %v", err)
+	}
+}
+
+func TestDecodeSimpleQuery(t *testing.T) {
+	c := mockClient(t, serveGIF)
+
+	img, format, err := c.DecodeSimpleQuery("1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "gif" {
+		t.Errorf("expected gif, got %s", format)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 1 || bounds.Dy() != 1 {
+		t.Errorf("expected a 1x1 image, got %v", bounds)
+	}
+
+	c = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not an image"))
+	})
+	if _, _, err := c.DecodeSimpleQuery("1+1", nil); err == nil {
+		t.Error("expected an error for a response that is not an image")
+	}
+}
+
+func TestSimpleQueryOptions(t *testing.T) {
+	var query url.Values
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		serveGIF(w, r)
+	})
+
+	body, _, err := c.GetSimpleQueryWithOptions("weather in Berlin",
+		wolfram.WithLayout(wolfram.LayoutLabelBar),
+		wolfram.WithFontSize(18),
+		wolfram.WithBackground("#F5F5F5"),
+		wolfram.WithForeground("White"),
+		wolfram.WithUnits(wolfram.Metric),
+		wolfram.WithWidth(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+
+	expected := map[string]string{
+		"layout":     "labelbar",
+		"fontsize":   "18",
+		"background": "F5F5F5",
+		"foreground": "white",
+		"units":      "metric",
+		"width":      "500",
+	}
+	for key, value := range expected {
+		if query.Get(key) != value {
+			t.Errorf("expected %s=%s, got %s", key, value, query.Encode())
+		}
+	}
+}
+
+func TestSimpleQueryOptionsValidated(t *testing.T) {
+	for _, background := range []string{"transparent", "193,255,255", "0,0,0,0.5", "f55"} {
+		if _, err := wolfram.QueryParams(wolfram.WithBackground(background)); err != nil {
+			t.Errorf("expected background %s to be accepted: %v", background, err)
+		}
+	}
+
+	invalid := []wolfram.QueryOption{
+		wolfram.WithLayout("grid"),
+		wolfram.WithFontSize(0),
+		wolfram.WithBackground("#F5F5F"),
+		wolfram.WithBackground("light grey"),
+		wolfram.WithForeground("red"),
+		wolfram.WithUnits(wolfram.Unit(7)),
+	}
+	for _, opt := range invalid {
+		if _, err := wolfram.QueryParams(opt); err == nil {
+			t.Error("expected an error for an invalid option")
+		}
+	}
+}
+
+func TestGetSimpleImage(t *testing.T) {
+	c := mockClient(t, serveGIF)
+
+	img, err := c.GetSimpleImage(context.Background(), "1+1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Body.Close()
+
+	if img.ContentType != "image/gif" || img.ContentLength != int64(len(gifImage)) {
+		t.Errorf("expected a gif of %d bytes, got %s of %d bytes", len(gifImage), img.ContentType, img.ContentLength)
+	}
+	if !strings.Contains(img.URL, "/v1/simple?") {
+		t.Errorf("expected the simple query url, got %s", img.URL)
+	}
+	data, err := io.ReadAll(img.Body)
+	if err != nil || !bytes.Equal(data, gifImage) {
+		t.Errorf("expected the body to be the image (%v)", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetSimpleImage(ctx, "1+1", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled context to fail the request, got %v", err)
+	}
+}
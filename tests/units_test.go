@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestUnitStrings(t *testing.T) {
+	for _, unit := range []wolfram.Unit{wolfram.Imperial, wolfram.Metric} {
+		parsed, err := wolfram.ParseUnit(unit.String())
+		if err != nil || parsed != unit {
+			t.Errorf("expected %s to parse back, got %v (%v)", unit, parsed, err)
+		}
+	}
+	if s := fmt.Sprint(wolfram.Metric); s != "metric" {
+		t.Errorf("expected metric, got %s", s)
+	}
+	if unit, err := wolfram.ParseUnit(" Imperial "); err != nil || unit != wolfram.Imperial {
+		t.Errorf("expected imperial, got %v (%v)", unit, err)
+	}
+	if _, err := wolfram.ParseUnit("furlongs"); err == nil {
+		t.Error("expected an error for an unknown unit system")
+	}
+	if s := wolfram.Unit(7).String(); s != "Unit(7)" {
+		t.Errorf("expected Unit(7), got %s", s)
+	}
+}
+
+func TestModeStrings(t *testing.T) {
+	for _, mode := range []wolfram.Mode{wolfram.Default, wolfram.Voice} {
+		parsed, err := wolfram.ParseMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("expected %s to parse back, got %v (%v)", mode, parsed, err)
+		}
+	}
+	if mode, err := wolfram.ParseMode("voice"); err != nil || mode != wolfram.Voice {
+		t.Errorf("expected Voice, got %v (%v)", mode, err)
+	}
+	if _, err := wolfram.ParseMode("loud"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
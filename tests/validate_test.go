@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetValidateQuery(t *testing.T) {
+	var path, input string
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		input = r.URL.Query().Get("input")
+		w.Write(fixture(t, "validate_query.json"))
+	})
+
+	res, err := c.GetValidateQuery("pi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v2/validatequery" || input != "pi" {
+		t.Errorf("unexpected request of %s for '%s'", path, input)
+	}
+
+	if !res.Success || res.Error.Err != nil {
+		t.Errorf("expected success, got %+v", res)
+	}
+	if res.Timing != 0.276 || res.ParseTiming != 0.211 {
+		t.Errorf("unexpected timing %v, parse timing %v", res.Timing, res.ParseTiming)
+	}
+	if len(res.Assumptions.Assumption) != 1 || len(res.Assumptions.Assumption[0].Values) != 2 {
+		t.Errorf("expected the clash assumption with 2 values, got %+v", res.Assumptions)
+	}
+}
+
+func TestGetValidateQueryNotUnderstood(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"validatequeryresult": {"success": false, "error": false, "timing": 0.1, "parsetiming": 0.1, "version": "2.6"}}`))
+	})
+
+	res, err := c.GetValidateQuery("fhqwhgads fhqwhgads", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Success {
+		t.Error("expected the query to not be understood")
+	}
+}
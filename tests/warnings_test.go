@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	wolfram "wolframAlpha"
+)
+
+func TestSuggestedQuery(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "spellcheck.json"))
+
+	res, err := c.GetQueryResult("wether in Tokio", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Query != "wether in Tokio" {
+		t.Errorf("expected the query as given, got %q", res.Query)
+	}
+	if suggested, ok := res.SuggestedQuery(); !ok || suggested != "weather in Tokyo" {
+		t.Errorf("expected suggested query 'weather in Tokyo', got %q (%t)", suggested, ok)
+	}
+}
+
+func TestSuggestedQueryWithOneSpellcheck(t *testing.T) {
+	c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"queryresult": {"success": true, "error": false, "warnings": {"count": 1,
+			"spellcheck": {"word": "feild", "suggestion": "field", "text": "Interpreting \"feild\" as \"field\""}}}}`))
+	})
+
+	res, err := c.GetQueryResult("magnetic feild of earth", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggested, ok := res.SuggestedQuery(); !ok || suggested != "magnetic field of earth" {
+		t.Errorf("expected suggested query 'magnetic field of earth', got %q (%t)", suggested, ok)
+	}
+}
+
+func TestSuggestedQueryWithoutSpellchecks(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "timed_out.json"))
+
+	res, err := c.GetQueryResult("paris", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggested, ok := res.SuggestedQuery(); ok {
+		t.Errorf("expected no suggested query, got %q", suggested)
+	}
+}
+
+func TestReInterpretedQuery(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "reinterpret.json"))
+
+	res, err := c.GetQueryResult("how big are the bluest whales swimming", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reinterpreted, ok := res.ReInterpretedQuery(); !ok || reinterpreted != "blue whale" {
+		t.Errorf("expected reinterpreted query 'blue whale', got %q (%t)", reinterpreted, ok)
+	}
+	if _, ok := res.SuggestedQuery(); ok {
+		t.Error("expected no suggested query without spelling warnings")
+	}
+
+	res, err = mockClient(t, serveFixture(t, "spellcheck.json")).GetQueryResult("wether in Tokio", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reinterpreted, ok := res.ReInterpretedQuery(); ok {
+		t.Errorf("expected no reinterpreted query, got %q", reinterpreted)
+	}
+}
+
+func TestReInterpretationAlternatives(t *testing.T) {
+	c := mockClient(t, serveFixture(t, "reinterpret.json"))
+
+	res, err := c.GetQueryResult("how big are the bluest whales swimming", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reinterpretation := res.Warnings.ReInterpretations[0]
+	expected := wolfram.Alternatives{
+		{Value: "blue", Score: "0.385429", Level: "medium"},
+		{Value: "whales", Score: "0.2", Level: "low"},
+	}
+	if !reflect.DeepEqual(reinterpretation.Alternatives, expected) {
+		t.Errorf("expected alternatives %+v, got %+v", expected, reinterpretation.Alternatives)
+	}
+	if queries := reinterpretation.AlternativeQueries(); !reflect.DeepEqual(queries, []string{"blue", "whales"}) {
+		t.Errorf("expected alternative queries [blue whales], got %v", queries)
+	}
+}
+
+func TestReInterpretationAlternativeShapes(t *testing.T) {
+	tests := map[string]wolfram.Alternatives{
+		`{"val": "blue", "score": "0.38", "level": "medium"}`: {{Value: "blue", Score: "0.38", Level: "medium"}},
+		`["blue", "whales"]`: {{Value: "blue"}, {Value: "whales"}},
+		`null`:               nil,
+	}
+	for alternatives, expected := range tests {
+		var reinterpretation wolfram.ReInterpretation
+		if err := json.Unmarshal([]byte(`{"new": "blue whale", "alternative": `+alternatives+`}`), &reinterpretation); err != nil {
+			t.Errorf("%s: %v", alternatives, err)
+			continue
+		}
+		if !reflect.DeepEqual(reinterpretation.Alternatives, expected) {
+			t.Errorf("%s: expected %+v, got %+v", alternatives, expected, reinterpretation.Alternatives)
+		}
+	}
+}
+
+func TestGeneralizations(t *testing.T) {
+	tests := []struct {
+		generalization string
+		count          int
+	}{
+		{`{"topic": "Price of gold", "desc": "General results for:", "url": "https://www5b.wolframalpha.com/api/v1/generalization.jsp?id=MSP123&s=12"}`, 1},
+		{`[{"topic": "Price of gold", "desc": "General results for:"}, {"topic": "Gold", "desc": "General results for:"}]`, 2},
+		{`null`, 0},
+	}
+	for _, test := range tests {
+		generalization, count := test.generalization, test.count
+		c := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"queryresult": {"success": true, "error": false, "generalization": ` + generalization + `}}`))
+		})
+		res, err := c.GetQueryResult("price of gold in 1980 in London", nil)
+		if err != nil {
+			t.Errorf("%s: %v", generalization, err)
+			continue
+		}
+		if len(res.Generalizations) != count || res.HasGeneralizations() != (count > 0) {
+			t.Errorf("%s: expected %d generalizations, got %+v", generalization, count, res.Generalizations)
+		}
+		if count > 0 && res.Generalizations[0].Topic != "Price of gold" {
+			t.Errorf("%s: unexpected generalization %+v", generalization, res.Generalizations[0])
+		}
+	}
+}
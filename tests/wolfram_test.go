@@ -3,7 +3,7 @@ package tests
 import (
 	"testing"
 
-	"github.com/johnha/go-wolfram"
+	wolfram "wolframAlpha"
 )
 
 const WOLFRAM_APPID = "DEMO"
@@ -51,7 +51,7 @@ func TestGetShortAnswerQueryResult(t *testing.T) {
 func TestGetSpokenAnswerResult(t *testing.T) {
 	c := &wolfram.Client{AppID: WOLFRAM_APPID}
 
-	_, err := c.GetSpokentAnswerQuery("Price of gold", wolfram.Metric, 0)
+	_, err := c.GetSpokenAnswerQuery("Price of gold", wolfram.Metric, 0)
 	if err != nil {
 		t.Failed()
 		t.Log(err.Error())
@@ -0,0 +1,78 @@
+package wolfram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// ValidationResult is the result of the validate query endpoint, which parses a query without computing the result.
+type ValidationResult struct {
+	// true if the input could be understood
+	Success bool `json:"success"`
+
+	// If no error, this will be false.  If an error, this is an object with a code and description
+	Error QueryError `json:"error"`
+
+	// The wall-clock time in seconds required to validate the query
+	Timing float64 `json:"timing"`
+
+	// The time in seconds required by the parsing phase
+	ParseTiming float64 `json:"parsetiming"`
+
+	// The assumptions made in parsing the query, as for a full result
+	Assumptions Assumptions `json:"assumptions"`
+
+	// Warnings hold information about for example spelling errors
+	Warnings Warnings `json:"warnings"`
+
+	// The version specification of the API on the server that produced this result
+	Version string `json:"version"`
+}
+
+// GetValidateQuery checks whether Wolfram Alpha understands the query and how it was parsed, without computing the
+//	full result.  This is much quicker than GetQueryResult, e.g. to check user input before querying.
+func (c *Client) GetValidateQuery(query string, params url.Values) (*ValidationResult, error) {
+	end, err := c.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	ctx := context.Background()
+	if err := c.checkAppID(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := checkReservedParams(params, "input", "appid", "output"); err != nil {
+		return nil, err
+	}
+
+	validateURL := fmt.Sprintf("%s/v2/validatequery?input=%s&appid=%s&output=%s",
+		c.baseURL(), url.QueryEscape(query), c.appID(ctx), OutputJSON)
+	if params != nil {
+		validateURL += "&" + params.Encode()
+	}
+
+	res, err := c.get(ctx, "validatequery", validateURL)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error in wolfram alpha http request")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error in obtaining full wolfram alpha http result")
+	}
+
+	data := struct {
+		Result ValidationResult `json:"validatequeryresult"`
+	}{}
+	if err := jsonLib.Unmarshal(body, &data); err != nil {
+		return nil, errors.WithMessage(err, "unable to interpret wolfram alpha json result")
+	}
+	return &data.Result, nil
+}
@@ -0,0 +1,51 @@
+package wolfram
+
+import "regexp"
+
+// SuggestedQuery returns the Query with the suggestion of each spelling warning applied, e.g. "weather in Tokio"
+//	becoming "weather in Tokyo".   Words are replaced whole and ignoring case.   ok is false if there were no spelling
+//	warnings.
+func (result *QueryResult) SuggestedQuery() (suggested string, ok bool) {
+	if len(result.Warnings.Spellchecks) == 0 {
+		return "", false
+	}
+
+	suggested = result.Query
+	for _, spellcheck := range result.Warnings.Spellchecks {
+		if spellcheck.Word == "" {
+			continue
+		}
+		word := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(spellcheck.Word) + `\b`)
+		suggested = word.ReplaceAllLiteralString(suggested, spellcheck.Suggestion)
+	}
+	return suggested, true
+}
+
+// ReInterpretedQuery returns the query Wolfram Alpha used in place of the Query when it did not understand it, e.g. to
+//	show "showing results for ... instead".   ok is false if the query was not reinterpreted.
+func (result *QueryResult) ReInterpretedQuery() (reinterpreted string, ok bool) {
+	for _, reinterpretation := range result.Warnings.ReInterpretations {
+		if reinterpretation.New != "" {
+			return reinterpretation.New, true
+		}
+	}
+	return "", false
+}
+
+// AlternativeQueries returns the text of the other queries Wolfram Alpha considered, in the order given, skipping any
+//	that are empty.
+func (reinterpretation *ReInterpretation) AlternativeQueries() []string {
+	queries := make([]string, 0, len(reinterpretation.Alternatives))
+	for _, alternative := range reinterpretation.Alternatives {
+		if alternative.Value != "" {
+			queries = append(queries, alternative.Value)
+		}
+	}
+	return queries
+}
+
+// HasGeneralizations reports whether Wolfram Alpha offered a more general query with more information, e.g. for
+//	"explore more" links.
+func (result *QueryResult) HasGeneralizations() bool {
+	return len(result.Generalizations) > 0
+}